@@ -1,18 +1,117 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
 
 	"domainshell/internal/api"
+	"domainshell/internal/cache"
 	"domainshell/internal/commands"
+	"domainshell/internal/config"
+	"domainshell/internal/currency"
+	"domainshell/internal/dnscheck"
 	"domainshell/internal/history"
+	"domainshell/internal/output"
+	"domainshell/internal/paths"
+	"domainshell/internal/provider"
 	"domainshell/internal/repl"
+	"domainshell/internal/results"
 	"domainshell/internal/version"
+	"domainshell/internal/whois"
 )
 
+type options struct {
+	showVersion  bool
+	outputFormat string
+	timeout      time.Duration
+	providerName string
+	baseURL      string
+	rate         float64
+	burst        int
+	noCache      bool
+	dnsPrecheck  bool
+	resolver     string
+	currencyCode string
+	locale       string
+	private      bool
+	dataDir      string
+	retries      int
+}
+
+func newFlags(cfg config.Config) (*flag.FlagSet, *options) {
+	opts := &options{}
+	flags := flag.NewFlagSet("domainshell", flag.ExitOnError)
+	flags.BoolVar(&opts.showVersion, "version", false, "print version information and exit")
+	flags.BoolVar(&opts.showVersion, "v", false, "print version information and exit")
+	flags.StringVar(&opts.outputFormat, "output", cfg.Output, "output format: table, plain, json or ndjson")
+	flags.StringVar(&opts.outputFormat, "o", cfg.Output, "shorthand for --output")
+	flags.DurationVar(&opts.timeout, "timeout", api.DefaultTimeout, "per-request deadline for API calls (0 disables it)")
+	flags.StringVar(&opts.providerName, "provider", cfg.Provider, "availability provider: "+strings.Join(provider.Names(), ", "))
+	flags.StringVar(&opts.baseURL, "base-url", cfg.BaseURL, "API base URL for the limoo provider")
+	flags.Float64Var(&opts.rate, "rate", cfg.RateLimit, "maximum API requests per second (0 disables the limit)")
+	flags.IntVar(&opts.burst, "burst", cfg.RateBurst, "number of API requests allowed in a burst")
+	flags.BoolVar(&opts.noCache, "no-cache", !cfg.Cache, "bypass the on-disk response cache")
+	flags.BoolVar(&opts.dnsPrecheck, "dns-precheck", cfg.DNSPrecheck, "mark names that resolve in DNS as registered without calling the API")
+	flags.StringVar(&opts.resolver, "resolver", cfg.Resolver, "DNS resolver used by the pre-check and the dns provider")
+	flags.StringVar(&opts.currencyCode, "currency", cfg.Currency, "show prices in IRT (Toman), IRR, USD or EUR")
+	flags.StringVar(&opts.locale, "locale", cfg.Locale, "price formatting locale: en or fa")
+	flags.BoolVar(&opts.private, "private", false, "incognito mode: keep history, cache and results in memory only")
+	flags.StringVar(&opts.dataDir, "data-dir", "", "keep config, data, cache and state in this directory (env: "+paths.EnvHome+")")
+	flags.IntVar(&opts.retries, "retries", api.DefaultRetryPolicy.MaxAttempts, "maximum attempts for requests that fail with transient errors")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: domainshell [flags] [command [args...]]\n\n")
+		fmt.Fprintf(flags.Output(), "Without a command, domainshell starts the interactive shell.\n")
+		fmt.Fprintf(flags.Output(), "With a command, it runs it once and exits with status 0 (available),\n")
+		fmt.Fprintf(flags.Output(), "1 (taken) or 2 (error).\n\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	return flags, opts
+}
+
+func dataDirFlag(args []string) string {
+	flags, opts := newFlags(config.Default())
+	flags.Init("domainshell", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	_ = flags.Parse(args)
+	return opts.dataDir
+}
+
 func main() {
-	if len(os.Args) > 1 && (os.Args[1] == "--version" || os.Args[1] == "-v") {
+	dirs, err := paths.Resolve(dataDirFlag(os.Args[1:]), os.LookupEnv)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	if err := dirs.Migrate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	cfg := loadConfig(dirs.ConfigFile())
+
+	flags, opts := newFlags(cfg)
+	_ = flags.Parse(os.Args[1:])
+
+	cfg.Output = opts.outputFormat
+	cfg.Provider = opts.providerName
+	cfg.BaseURL = opts.baseURL
+	cfg.RateLimit = opts.rate
+	cfg.RateBurst = opts.burst
+	cfg.Cache = !opts.noCache
+	cfg.DNSPrecheck = opts.dnsPrecheck
+	cfg.Resolver = opts.resolver
+	cfg.Currency = opts.currencyCode
+	cfg.Locale = opts.locale
+
+	if opts.showVersion {
 		fmt.Printf("domainshell %s\n", version.Version)
 		if version.BuildDate != "" {
 			fmt.Printf("Build date: %s\n", version.BuildDate)
@@ -23,14 +122,56 @@ func main() {
 		os.Exit(0)
 	}
 
-	apiClient := api.NewClient()
-	cmds := commands.NewCommands(apiClient)
+	format, err := output.ParseFormat(opts.outputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+
+	retryPolicy := api.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = opts.retries
 
-	hist, err := history.NewHistory()
+	providerName := strings.ToLower(opts.providerName)
+	apiClient, err := provider.New(providerName, provider.Options{
+		BaseURL:  opts.baseURL,
+		CacheDir: dirs.Cache,
+		Resolver: opts.resolver,
+		Timeout:  opts.timeout,
+		Retry:    retryPolicy,
+		Limiter:  api.NewRateLimiter(opts.rate, opts.burst),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	cmds := commands.NewCommands(withCache(apiClient, cfg, dirs.ResponseCacheDir(providerName), opts.noCache))
+	cmds.SetConfig(cfg, dirs.ConfigFile())
+	cmds.SetProvider(providerName)
+	cmds.SetOutputFormat(format)
+	cmds.SetRates(ratesSource(cfg, dirs))
+	cmds.SetTLDFile(dirs.TLDFile())
+	cmds.SetStore(results.NewStore(dirs.ResultsFile()))
+	if isatty.IsTerminal(os.Stderr.Fd()) {
+		cmds.SetProgress(os.Stderr)
+	}
+	if err := setCurrency(context.Background(), cmds, opts.currencyCode, opts.locale); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, showing prices in Toman\n", err)
+	}
+	cmds.SetWhois(whois.NewClient(whois.WithTimeout(opts.timeout)))
+	cmds.SetDNSChecker(dnscheck.NewChecker(opts.resolver, 0))
+	cmds.EnableDNSPrecheck(opts.dnsPrecheck)
+	cmds.SetPrivate(opts.private)
+
+	if flags.NArg() > 0 {
+		os.Exit(runOnce(cmds, flags.Args()))
+	}
+
+	hist, err := history.NewHistory(dirs.HistoryFile())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to initialize history: %v\n", err)
 		hist = history.NewEmptyHistory()
 	}
+	hist.SetLimit(cfg.HistorySize)
 
 	r, err := repl.NewREPL(cmds, hist)
 	if err != nil {
@@ -43,3 +184,79 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+func withCache(client api.ClientInterface, cfg config.Config, dir string, disabled bool) api.ClientInterface {
+	if disabled {
+		return client
+	}
+
+	cached, err := cache.New(client, dir, time.Duration(cfg.CacheTTLAvailability), time.Duration(cfg.CacheTTLSuggestions))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cache disabled: %v\n", err)
+		return client
+	}
+	return cached
+}
+
+func ratesSource(cfg config.Config, dirs paths.Paths) *currency.Source {
+	path := cfg.RatesFile
+	if path == "" {
+		path = dirs.RatesFile()
+		if cfg.RatesURL != "" {
+			path = dirs.RatesCacheFile()
+		}
+	}
+	return &currency.Source{Path: path, URL: cfg.RatesURL}
+}
+
+func setCurrency(ctx context.Context, cmds *commands.Commands, code, locale string) error {
+	if code == "" && locale == "" {
+		return nil
+	}
+
+	c := currency.IRT
+	if code != "" {
+		var err error
+		if c, err = currency.ParseCode(code); err != nil {
+			return err
+		}
+	}
+
+	l := currency.LocaleEnglish
+	if locale != "" {
+		var err error
+		if l, err = currency.ParseLocale(locale); err != nil {
+			return err
+		}
+	}
+
+	return cmds.SetCurrency(ctx, c, l)
+}
+
+func loadConfig(path string) config.Config {
+	cfg, err := config.Load(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
+	}
+
+	if err := cfg.ApplyEnv(os.LookupEnv); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return cfg
+}
+
+func runOnce(cmds *commands.Commands, args []string) int {
+	command, rest := cmds.ParseInput(strings.Join(args, " "))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := cmds.Run(ctx, command, rest); err != nil {
+		if errors.Is(err, commands.ErrUnknownCommand) || errors.Is(err, commands.ErrInteractive) {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		return commands.OutcomeError.ExitCode()
+	}
+
+	return cmds.LastOutcome().ExitCode()
+}
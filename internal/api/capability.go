@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnsupported = errors.New("not supported by this provider")
+
+type Capability uint8
+
+const (
+	CapabilityCheck Capability = 1 << iota
+	CapabilitySuggest
+	CapabilityPricing
+)
+
+const AllCapabilities = CapabilityCheck | CapabilitySuggest | CapabilityPricing
+
+var capabilityNames = []struct {
+	capability Capability
+	name       string
+}{
+	{CapabilityCheck, "check"},
+	{CapabilitySuggest, "suggest"},
+	{CapabilityPricing, "pricing"},
+}
+
+func (c Capability) String() string {
+	var names []string
+	for _, cn := range capabilityNames {
+		if c&cn.capability != 0 {
+			names = append(names, cn.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ",")
+}
+
+type CapabilityProvider interface {
+	Capabilities() Capability
+}
+
+func Supports(client ClientInterface, capability Capability) bool {
+	if p, ok := client.(CapabilityProvider); ok {
+		return p.Capabilities()&capability == capability
+	}
+	return true
+}
+
+func (c *Client) Capabilities() Capability {
+	return AllCapabilities
+}
@@ -0,0 +1,55 @@
+package api
+
+import "testing"
+
+type checkOnlyClient struct {
+	ClientInterface
+}
+
+func (c *checkOnlyClient) Capabilities() Capability {
+	return CapabilityCheck
+}
+
+type legacyClient struct {
+	ClientInterface
+}
+
+func TestSupports(t *testing.T) {
+	tests := []struct {
+		name       string
+		client     ClientInterface
+		capability Capability
+		expected   bool
+	}{
+		{name: "limoo supports suggestions", client: NewClient(), capability: CapabilitySuggest, expected: true},
+		{name: "declared capability", client: &checkOnlyClient{}, capability: CapabilityCheck, expected: true},
+		{name: "missing capability", client: &checkOnlyClient{}, capability: CapabilitySuggest, expected: false},
+		{name: "partially supported set", client: &checkOnlyClient{}, capability: CapabilityCheck | CapabilityPricing, expected: false},
+		{name: "undeclared clients support everything", client: &legacyClient{}, capability: CapabilityPricing, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Supports(tt.client, tt.capability); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCapability_String(t *testing.T) {
+	tests := []struct {
+		capability Capability
+		expected   string
+	}{
+		{AllCapabilities, "check,suggest,pricing"},
+		{CapabilityCheck, "check"},
+		{0, "none"},
+	}
+
+	for _, tt := range tests {
+		if s := tt.capability.String(); s != tt.expected {
+			t.Errorf("Expected %q, got %q", tt.expected, s)
+		}
+	}
+}
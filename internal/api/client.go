@@ -1,73 +1,172 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"domainshell/pkg/domain"
 )
 
 const defaultBaseURL = "https://edge.limoo.host/v1/domain"
 
+const MaxBatchSize = 20
+
+const DefaultTimeout = 15 * time.Second
+
 type ClientInterface interface {
 	CheckAvailability(domainName string) (*domain.Response, error)
+	CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error)
+	CheckAvailabilityBatch(domainNames []string) (*domain.Response, error)
+	CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error)
 	SuggestDomains(domainName string) (*domain.Response, error)
+	SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error)
 }
 
 type Client struct {
 	httpClient *http.Client
 	baseURL    string
+	timeout    time.Duration
+	retry      RetryPolicy
+	limiter    *RateLimiter
 }
 
-func NewClient() *Client {
-	return &Client{
-		httpClient: &http.Client{},
-		baseURL:    defaultBaseURL,
+type Option func(*Client)
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
 	}
 }
 
-func NewClientWithBaseURL(baseURL string) *Client {
-	return &Client{
+func NewClient(opts ...Option) *Client {
+	return NewClientWithBaseURL(defaultBaseURL, opts...)
+}
+
+func NewClientWithBaseURL(baseURL string, opts ...Option) *Client {
+	c := &Client{
 		httpClient: &http.Client{},
 		baseURL:    baseURL,
+		timeout:    DefaultTimeout,
+		retry:      DefaultRetryPolicy,
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
 func (c *Client) CheckAvailability(domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (c *Client) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
 	q := url.Values{}
 	q.Add("domain[]", domainName)
 
-	reqURL := fmt.Sprintf("%s/check-availability?%s", c.baseURL, q.Encode())
+	return c.get(ctx, fmt.Sprintf("%s/check-availability?%s", c.baseURL, q.Encode()))
+}
 
-	resp, err := c.httpClient.Get(reqURL)
-	if err != nil {
-		return nil, fmt.Errorf("request error: %w", err)
-	}
-	defer resp.Body.Close()
+func (c *Client) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
 
-	var result domain.Response
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode error: %w", err)
+func (c *Client) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	result := &domain.Response{Data: make([]domain.DomainData, 0, len(domainNames))}
+
+	for start := 0; start < len(domainNames); start += MaxBatchSize {
+		end := min(start+MaxBatchSize, len(domainNames))
+		chunk := domainNames[start:end]
+
+		q := url.Values{}
+		for _, name := range chunk {
+			q.Add("domain[]", name)
+		}
+
+		resp, err := c.get(ctx, fmt.Sprintf("%s/check-availability?%s", c.baseURL, q.Encode()))
+		if err != nil {
+			return nil, err
+		}
+
+		result.Data = append(result.Data, orderByInput(chunk, resp.Data)...)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
 func (c *Client) SuggestDomains(domainName string) (*domain.Response, error) {
-	reqURL := fmt.Sprintf("%s/suggest?domain=%s", c.baseURL, url.QueryEscape(domainName))
+	return c.SuggestDomainsContext(context.Background(), domainName)
+}
+
+func (c *Client) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.get(ctx, fmt.Sprintf("%s/suggest?domain=%s", c.baseURL, url.QueryEscape(domainName)))
+}
+
+func (c *Client) get(ctx context.Context, reqURL string) (*domain.Response, error) {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("request error: %w", err)
+		}
+
+		result, err := c.do(ctx, reqURL)
+		if err == nil || attempt >= c.retry.Attempts() || !c.retry.Retryable(err) || ctx.Err() != nil {
+			return result, err
+		}
 
-	resp, err := c.httpClient.Get(reqURL)
+		if err := c.retry.Wait(ctx, attempt, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, reqURL string) (*domain.Response, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("request error: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, ErrorFromResponse(resp)
+	}
+
 	var result domain.Response
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode error: %w", err)
+		return nil, &Error{Kind: ErrMalformedResponse, StatusCode: resp.StatusCode, Err: err}
 	}
 
 	return &result, nil
 }
+
+func orderByInput(names []string, data []domain.DomainData) []domain.DomainData {
+	byName := make(map[string]domain.DomainData, len(data))
+	for _, item := range data {
+		byName[strings.ToLower(item.Domain)] = item
+	}
+
+	ordered := make([]domain.DomainData, 0, len(names))
+	for _, name := range names {
+		if item, ok := byName[strings.ToLower(name)]; ok {
+			ordered = append(ordered, item)
+		}
+	}
+
+	return ordered
+}
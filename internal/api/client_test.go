@@ -1,10 +1,14 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"domainshell/pkg/domain"
 )
@@ -28,14 +32,8 @@ func TestClient_CheckAvailability(t *testing.T) {
 						Domain:    "example.com",
 						OnSale:    false,
 						Premium:   false,
-						Prices: struct {
-							Register struct {
-								OneYear int `json:"1y"`
-							} `json:"register"`
-						}{
-							Register: struct {
-								OneYear int `json:"1y"`
-							}{
+						Prices: domain.Prices{
+							Register: domain.PeriodPrices{
 								OneYear: 100000,
 							},
 						},
@@ -90,7 +88,7 @@ func TestClient_CheckAvailability(t *testing.T) {
 			}))
 			defer server.Close()
 
-			client := NewClientWithBaseURL(server.URL + "/v1/domain")
+			client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(NoRetry))
 			client.httpClient = server.Client()
 
 			result, err := client.CheckAvailability(tt.domain)
@@ -119,6 +117,96 @@ func TestClient_CheckAvailability(t *testing.T) {
 	}
 }
 
+func TestClient_CheckAvailabilityBatch(t *testing.T) {
+	tests := []struct {
+		name             string
+		domains          []string
+		expectedRequests int
+	}{
+		{
+			name:             "single request",
+			domains:          []string{"example.com", "example.net", "example.ir"},
+			expectedRequests: 1,
+		},
+		{
+			name:             "chunked requests",
+			domains:          generateDomains(MaxBatchSize*2 + 5),
+			expectedRequests: 3,
+		},
+		{
+			name:             "no domains",
+			domains:          []string{},
+			expectedRequests: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				names := r.URL.Query()["domain[]"]
+				if len(names) > MaxBatchSize {
+					t.Errorf("Expected at most %d domains per request, got %d", MaxBatchSize, len(names))
+				}
+
+				// Answer in reverse order to make sure the client restores input order.
+				var response domain.Response
+				for i := len(names) - 1; i >= 0; i-- {
+					response.Data = append(response.Data, domain.DomainData{Domain: names[i], Available: i%2 == 0})
+				}
+				json.NewEncoder(w).Encode(response)
+			}))
+			defer server.Close()
+
+			client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(NoRetry))
+			client.httpClient = server.Client()
+
+			result, err := client.CheckAvailabilityBatch(tt.domains)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if requests != tt.expectedRequests {
+				t.Errorf("Expected %d requests, got %d", tt.expectedRequests, requests)
+			}
+
+			if len(result.Data) != len(tt.domains) {
+				t.Fatalf("Expected %d results, got %d", len(tt.domains), len(result.Data))
+			}
+
+			for i, name := range tt.domains {
+				if result.Data[i].Domain != name {
+					t.Errorf("Result %d: expected domain %s, got %s", i, name, result.Data[i].Domain)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_CheckAvailabilityBatchServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(NoRetry))
+	client.httpClient = server.Client()
+
+	if _, err := client.CheckAvailabilityBatch([]string{"example.com", "example.net"}); err == nil {
+		t.Error("Expected error but got none")
+	}
+}
+
+func generateDomains(n int) []string {
+	domains := make([]string, n)
+	for i := range domains {
+		domains[i] = fmt.Sprintf("example%d.com", i)
+	}
+	return domains
+}
+
 func TestClient_SuggestDomains(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -169,7 +257,7 @@ func TestClient_SuggestDomains(t *testing.T) {
 			}))
 			defer server.Close()
 
-			client := NewClientWithBaseURL(server.URL + "/v1/domain")
+			client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(NoRetry))
 			client.httpClient = server.Client()
 
 			result, err := client.SuggestDomains(tt.domain)
@@ -193,6 +281,129 @@ func TestClient_SuggestDomains(t *testing.T) {
 	}
 }
 
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name            string
+		statusCode      int
+		contentType     string
+		body            string
+		expectedKind    error
+		expectedMessage string
+	}{
+		{
+			name:            "rate limited",
+			statusCode:      http.StatusTooManyRequests,
+			contentType:     "application/json",
+			body:            `{"message": "Too Many Attempts."}`,
+			expectedKind:    ErrRateLimited,
+			expectedMessage: "Too Many Attempts.",
+		},
+		{
+			name:         "server error with html page",
+			statusCode:   http.StatusBadGateway,
+			contentType:  "text/html",
+			body:         "<html><body>502 Bad Gateway</body></html>",
+			expectedKind: ErrServerError,
+		},
+		{
+			name:            "invalid domain",
+			statusCode:      http.StatusUnprocessableEntity,
+			contentType:     "application/json",
+			body:            `{"errors": {"domain.0": ["The domain format is invalid."]}}`,
+			expectedKind:    ErrInvalidDomain,
+			expectedMessage: "The domain format is invalid.",
+		},
+		{
+			name:         "malformed response",
+			statusCode:   http.StatusOK,
+			contentType:  "text/html",
+			body:         "<html>maintenance</html>",
+			expectedKind: ErrMalformedResponse,
+		},
+		{
+			name:            "unexpected status",
+			statusCode:      http.StatusForbidden,
+			contentType:     "application/json",
+			body:            `{"error": "forbidden"}`,
+			expectedKind:    ErrUnexpectedStatus,
+			expectedMessage: "forbidden",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.statusCode)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(NoRetry))
+			client.httpClient = server.Client()
+
+			_, err := client.CheckAvailability("example.com")
+			if !errors.Is(err, tt.expectedKind) {
+				t.Fatalf("Expected %v, got %v", tt.expectedKind, err)
+			}
+
+			var apiErr *Error
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("Expected *Error, got %T", err)
+			}
+			if apiErr.StatusCode != tt.statusCode {
+				t.Errorf("Expected status %d, got %d", tt.statusCode, apiErr.StatusCode)
+			}
+			if apiErr.Message != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, apiErr.Message)
+			}
+		})
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithTimeout(50*time.Millisecond), WithRetryPolicy(NoRetry))
+	client.httpClient = server.Client()
+
+	_, err := client.CheckAvailability("example.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded error, got %v", err)
+	}
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(NoRetry))
+	client.httpClient = server.Client()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	_, err := client.SuggestDomainsContext(ctx, "example")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled error, got %v", err)
+	}
+}
+
 func TestNewClient(t *testing.T) {
 	client := NewClient()
 	if client == nil {
@@ -201,4 +412,12 @@ func TestNewClient(t *testing.T) {
 	if client.httpClient == nil {
 		t.Error("NewClient() httpClient is nil")
 	}
+	if client.timeout != DefaultTimeout {
+		t.Errorf("Expected default timeout %v, got %v", DefaultTimeout, client.timeout)
+	}
+
+	client = NewClient(WithTimeout(time.Second))
+	if client.timeout != time.Second {
+		t.Errorf("Expected timeout %v, got %v", time.Second, client.timeout)
+	}
 }
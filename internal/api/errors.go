@@ -0,0 +1,105 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+const maxErrorBodySize = 64 << 10
+
+var (
+	ErrRateLimited       = errors.New("rate limited")
+	ErrServerError       = errors.New("server error")
+	ErrInvalidDomain     = errors.New("invalid domain")
+	ErrMalformedResponse = errors.New("malformed response")
+	ErrUnexpectedStatus  = errors.New("unexpected status")
+)
+
+type Error struct {
+	Kind       error
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Kind.Error())
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " (HTTP %d)", e.StatusCode)
+	}
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	} else if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+type errorPayload struct {
+	Message string              `json:"message"`
+	Error   string              `json:"error"`
+	Errors  map[string][]string `json:"errors"`
+}
+
+func ErrorFromResponse(resp *http.Response) *Error {
+	e := &Error{
+		Kind:       kindForStatus(resp.StatusCode),
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return e
+	}
+
+	var payload errorPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return e
+	}
+
+	e.Message = payload.Message
+	if e.Message == "" {
+		e.Message = payload.Error
+	}
+	if e.Message == "" {
+		fields := slices.Sorted(maps.Keys(payload.Errors))
+		for _, field := range fields {
+			if msgs := payload.Errors[field]; len(msgs) > 0 {
+				e.Message = msgs[0]
+				break
+			}
+		}
+	}
+
+	return e
+}
+
+func kindForStatus(status int) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrInvalidDomain
+	case status >= 500:
+		return ErrServerError
+	default:
+		return ErrUnexpectedStatus
+	}
+}
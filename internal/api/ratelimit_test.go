@@ -0,0 +1,99 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	if l := NewRateLimiter(0, 5); l != nil {
+		t.Error("Expected nil limiter for zero rate")
+	}
+
+	var l *RateLimiter
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Nil limiter should not block, got %v", err)
+	}
+}
+
+func TestRateLimiter_Burst(t *testing.T) {
+	l := NewRateLimiter(1, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Burst should not wait, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_Throttles(t *testing.T) {
+	l := NewRateLimiter(50, 1)
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("Expected at least 100ms for 6 requests at 50/s, took %v", elapsed)
+	}
+}
+
+func TestRateLimiter_ContextCancellation(t *testing.T) {
+	l := NewRateLimiter(0.1, 1)
+	_ = l.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestClient_SharedRateLimiter(t *testing.T) {
+	var mu sync.Mutex
+	var times []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRateLimiter(NewRateLimiter(20, 1)))
+	client.httpClient = server.Client()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.CheckAvailability("example.com"); err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed := time.Since(start); elapsed < 180*time.Millisecond {
+		t.Errorf("Expected concurrent callers to share the limiter (>=200ms), took %v", elapsed)
+	}
+	if len(times) != 5 {
+		t.Errorf("Expected 5 requests, got %d", len(times))
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type RetryPolicy struct {
+	MaxAttempts       int
+	BaseDelay         time.Duration
+	MaxDelay          time.Duration
+	Jitter            float64
+	RespectRetryAfter bool
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:       3,
+	BaseDelay:         200 * time.Millisecond,
+	MaxDelay:          10 * time.Second,
+	Jitter:            0.2,
+	RespectRetryAfter: true,
+}
+
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+func (p RetryPolicy) Attempts() int {
+	return max(p.MaxAttempts, 1)
+}
+
+func (p RetryPolicy) Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServerError) || !errors.As(err, new(*Error))
+}
+
+func (p RetryPolicy) delay(attempt int, err error) time.Duration {
+	limit := p.MaxDelay
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+
+	d := limit
+	if shift := max(attempt-1, 0); shift < 63 && p.BaseDelay <= limit>>shift {
+		d = p.BaseDelay << shift
+	}
+	if p.Jitter > 0 {
+		jittered := float64(d) * (1 + p.Jitter*(2*rand.Float64()-1))
+		if jittered < float64(limit) {
+			d = time.Duration(jittered)
+		} else {
+			d = limit
+		}
+	}
+
+	var apiErr *Error
+	if p.RespectRetryAfter && errors.As(err, &apiErr) && apiErr.RetryAfter > d {
+		d = apiErr.RetryAfter
+	}
+	return d
+}
+
+func (p RetryPolicy) Wait(ctx context.Context, attempt int, err error) error {
+	var apiErr *Error
+	if deadline, ok := ctx.Deadline(); ok && p.RespectRetryAfter && errors.As(err, &apiErr) && apiErr.RetryAfter > time.Until(deadline) {
+		return err
+	}
+	if err := sleep(ctx, p.delay(attempt, err)); err != nil {
+		return fmt.Errorf("request error: %w", err)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
@@ -0,0 +1,262 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+var fastRetry = RetryPolicy{
+	MaxAttempts:       4,
+	BaseDelay:         time.Millisecond,
+	MaxDelay:          20 * time.Millisecond,
+	RespectRetryAfter: true,
+}
+
+func flakyServer(t *testing.T, failures int, status int, retryAfter string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(calls.Add(1)) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		json.NewEncoder(w).Encode(domain.Response{Data: []domain.DomainData{{Available: true, Domain: "example.com"}}})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &calls
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name          string
+		failures      int
+		status        int
+		expectError   error
+		expectedCalls int32
+	}{
+		{
+			name:          "recovers from server errors",
+			failures:      2,
+			status:        http.StatusServiceUnavailable,
+			expectedCalls: 3,
+		},
+		{
+			name:          "recovers from rate limiting",
+			failures:      1,
+			status:        http.StatusTooManyRequests,
+			expectedCalls: 2,
+		},
+		{
+			name:          "gives up after max attempts",
+			failures:      10,
+			status:        http.StatusInternalServerError,
+			expectError:   ErrServerError,
+			expectedCalls: 4,
+		},
+		{
+			name:          "does not retry invalid domains",
+			failures:      10,
+			status:        http.StatusUnprocessableEntity,
+			expectError:   ErrInvalidDomain,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, calls := flakyServer(t, tt.failures, tt.status, "")
+
+			client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(fastRetry))
+			client.httpClient = server.Client()
+
+			_, err := client.CheckAvailability("example.com")
+			if tt.expectError == nil && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.expectError != nil && !errors.Is(err, tt.expectError) {
+				t.Fatalf("Expected %v, got %v", tt.expectError, err)
+			}
+			if calls.Load() != tt.expectedCalls {
+				t.Errorf("Expected %d calls, got %d", tt.expectedCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestClient_RetrySuggest(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusBadGateway, "")
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(fastRetry))
+	client.httpClient = server.Client()
+
+	if _, err := client.SuggestDomains("example"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected 2 calls, got %d", calls.Load())
+	}
+}
+
+func TestClient_RetryCancelledDuringBackoff(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusServiceUnavailable, "")
+
+	policy := fastRetry
+	policy.BaseDelay = time.Minute
+	policy.MaxDelay = time.Minute
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(policy))
+	client.httpClient = server.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.CheckAvailabilityContext(ctx, "example.com")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected deadline exceeded, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected 1 call, got %d", calls.Load())
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{
+		BaseDelay:         100 * time.Millisecond,
+		MaxDelay:          time.Second,
+		RespectRetryAfter: true,
+	}
+
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		attempt  int
+		err      error
+		expected time.Duration
+	}{
+		{
+			name:     "first retry",
+			policy:   policy,
+			attempt:  1,
+			err:      &Error{Kind: ErrServerError},
+			expected: 100 * time.Millisecond,
+		},
+		{
+			name:     "exponential growth",
+			policy:   policy,
+			attempt:  3,
+			err:      &Error{Kind: ErrServerError},
+			expected: 400 * time.Millisecond,
+		},
+		{
+			name:     "capped by max delay",
+			policy:   policy,
+			attempt:  6,
+			err:      &Error{Kind: ErrServerError},
+			expected: time.Second,
+		},
+		{
+			name:     "honours retry-after",
+			policy:   policy,
+			attempt:  1,
+			err:      &Error{Kind: ErrRateLimited, RetryAfter: 500 * time.Millisecond},
+			expected: 500 * time.Millisecond,
+		},
+		{
+			name:     "retry-after is not capped by max delay",
+			policy:   policy,
+			attempt:  1,
+			err:      &Error{Kind: ErrRateLimited, RetryAfter: time.Minute},
+			expected: time.Minute,
+		},
+		{
+			name:     "large attempt does not overflow",
+			policy:   policy,
+			attempt:  40,
+			err:      &Error{Kind: ErrServerError},
+			expected: time.Second,
+		},
+		{
+			name:     "large attempt without max delay",
+			policy:   RetryPolicy{BaseDelay: 100 * time.Millisecond},
+			attempt:  100,
+			err:      &Error{Kind: ErrServerError},
+			expected: math.MaxInt64,
+		},
+		{
+			name:     "ignores retry-after when disabled",
+			policy:   RetryPolicy{BaseDelay: 100 * time.Millisecond},
+			attempt:  1,
+			err:      &Error{Kind: ErrRateLimited, RetryAfter: 500 * time.Millisecond},
+			expected: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if d := tt.policy.delay(tt.attempt, tt.err); d != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, d)
+			}
+		})
+	}
+}
+
+func TestClient_RetryAfterBeyondDeadline(t *testing.T) {
+	server, calls := flakyServer(t, 10, http.StatusTooManyRequests, "1")
+
+	client := NewClientWithBaseURL(server.URL+"/v1/domain", WithRetryPolicy(fastRetry))
+	client.httpClient = server.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.CheckAvailabilityContext(ctx, "example.com")
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("Expected ErrRateLimited, got %v", err)
+	}
+	if calls.Load() != 1 || time.Since(start) > 100*time.Millisecond {
+		t.Errorf("Expected to give up at once, got %d calls after %v", calls.Load(), time.Since(start))
+	}
+}
+
+func TestRetryPolicy_Jitter(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, Jitter: 0.5}
+
+	for i := 0; i < 100; i++ {
+		d := policy.delay(1, errors.New("network error"))
+		if d < 50*time.Millisecond || d > 150*time.Millisecond {
+			t.Fatalf("Delay %v outside jitter range", d)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if d := parseRetryAfter("3"); d != 3*time.Second {
+		t.Errorf("Expected 3s, got %v", d)
+	}
+	if d := parseRetryAfter(""); d != 0 {
+		t.Errorf("Expected 0, got %v", d)
+	}
+	if d := parseRetryAfter("soon"); d != 0 {
+		t.Errorf("Expected 0, got %v", d)
+	}
+
+	at := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if d := parseRetryAfter(at); d < 59*time.Minute || d > time.Hour {
+		t.Errorf("Expected about an hour, got %v", d)
+	}
+}
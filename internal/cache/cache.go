@@ -0,0 +1,307 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"domainshell/internal/api"
+	"domainshell/pkg/domain"
+)
+
+const (
+	kindAvailability = "check"
+	kindSuggestions  = "suggest"
+)
+
+type refreshKey struct{}
+
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func isRefresh(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+type entry struct {
+	Key      string          `json:"key"`
+	StoredAt time.Time       `json:"stored_at"`
+	Response domain.Response `json:"response"`
+}
+
+type Stats struct {
+	Entries int
+	Expired int
+	Size    int64
+	Hits    int
+	Misses  int
+}
+
+type Client struct {
+	next            api.ClientInterface
+	dir             string
+	availabilityTTL time.Duration
+	suggestionTTL   time.Duration
+
+	mu      sync.Mutex
+	hits    int
+	misses  int
+	private bool
+	memory  map[string]entry
+}
+
+func New(next api.ClientInterface, dir string, availabilityTTL, suggestionTTL time.Duration) (*Client, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	return &Client{
+		next:            next,
+		dir:             dir,
+		availabilityTTL: availabilityTTL,
+		suggestionTTL:   suggestionTTL,
+	}, nil
+}
+
+func (c *Client) CheckAvailability(domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (c *Client) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(ctx, []string{domainName})
+}
+
+func (c *Client) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
+
+func (c *Client) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	found := make(map[string]domain.DomainData, len(domainNames))
+	var missing []string
+
+	for _, name := range domainNames {
+		key := strings.ToLower(name)
+		if resp, ok := c.lookup(ctx, kindAvailability, key, c.availabilityTTL); ok && len(resp.Data) > 0 {
+			found[key] = resp.Data[0]
+			continue
+		}
+		missing = append(missing, name)
+	}
+
+	if len(missing) > 0 {
+		var resp *domain.Response
+		var err error
+		if len(missing) == 1 {
+			resp, err = c.next.CheckAvailabilityContext(ctx, missing[0])
+		} else {
+			resp, err = c.next.CheckAvailabilityBatchContext(ctx, missing)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Data {
+			key := strings.ToLower(item.Domain)
+			c.store(kindAvailability, key, &domain.Response{Data: []domain.DomainData{item}})
+			found[key] = item
+		}
+	}
+
+	result := &domain.Response{Data: make([]domain.DomainData, 0, len(domainNames))}
+	for _, name := range domainNames {
+		if item, ok := found[strings.ToLower(name)]; ok {
+			result.Data = append(result.Data, item)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) SuggestDomains(domainName string) (*domain.Response, error) {
+	return c.SuggestDomainsContext(context.Background(), domainName)
+}
+
+func (c *Client) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	key := strings.ToLower(domainName)
+	if resp, ok := c.lookup(ctx, kindSuggestions, key, c.suggestionTTL); ok {
+		return resp, nil
+	}
+
+	resp, err := c.next.SuggestDomainsContext(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(kindSuggestions, key, resp)
+	return resp, nil
+}
+
+func (c *Client) SetPrivate(private bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.private = private
+	if !private {
+		c.memory = nil
+	}
+}
+
+func (c *Client) Capabilities() api.Capability {
+	if p, ok := c.next.(api.CapabilityProvider); ok {
+		return p.Capabilities()
+	}
+	return api.AllCapabilities
+}
+
+func (c *Client) Stats() (Stats, error) {
+	c.mu.Lock()
+	stats := Stats{Hits: c.hits, Misses: c.misses, Entries: len(c.memory)}
+	c.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return stats, err
+	}
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		stats.Entries++
+		stats.Size += info.Size()
+
+		e, err := readEntry(file)
+		if err != nil || time.Since(e.StoredAt) > c.ttl(filepath.Base(file)) {
+			stats.Expired++
+		}
+	}
+
+	return stats, nil
+}
+
+func (c *Client) Clear() error {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	c.hits, c.misses = 0, 0
+	c.memory = nil
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) lookup(ctx context.Context, kind, key string, ttl time.Duration) (*domain.Response, bool) {
+	if isRefresh(ctx) || ttl <= 0 {
+		c.record(false)
+		return nil, false
+	}
+
+	path := c.path(kind, key)
+	c.mu.Lock()
+	e, ok := c.memory[path]
+	c.mu.Unlock()
+	if !ok {
+		stored, err := readEntry(path)
+		if err != nil {
+			c.record(false)
+			return nil, false
+		}
+		e = *stored
+	}
+
+	if e.Key != key || time.Since(e.StoredAt) > ttl {
+		c.record(false)
+		return nil, false
+	}
+
+	c.record(true)
+	resp := &domain.Response{Data: make([]domain.DomainData, len(e.Response.Data))}
+	for i, item := range e.Response.Data {
+		item.Cached = true
+		item.CachedAt = e.StoredAt
+		resp.Data[i] = item
+	}
+	return resp, true
+}
+
+func (c *Client) store(kind, key string, resp *domain.Response) {
+	e := entry{Key: key, StoredAt: time.Now(), Response: *resp}
+	path := c.path(kind, key)
+
+	c.mu.Lock()
+	if c.private {
+		if c.memory == nil {
+			c.memory = make(map[string]entry)
+		}
+		c.memory[path] = e
+		c.mu.Unlock()
+		return
+	}
+	c.mu.Unlock()
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, path)
+}
+
+func (c *Client) record(hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if hit {
+		c.hits++
+	} else {
+		c.misses++
+	}
+}
+
+func (c *Client) path(kind, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, kind+"-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+func (c *Client) ttl(fileName string) time.Duration {
+	if strings.HasPrefix(fileName, kindSuggestions+"-") {
+		return c.suggestionTTL
+	}
+	return c.availabilityTTL
+}
+
+func readEntry(path string) (*entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
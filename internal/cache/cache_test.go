@@ -0,0 +1,241 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+type countingClient struct {
+	checks   [][]string
+	suggests []string
+	err      error
+}
+
+func (c *countingClient) CheckAvailability(domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (c *countingClient) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(ctx, []string{domainName})
+}
+
+func (c *countingClient) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
+
+func (c *countingClient) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	c.checks = append(c.checks, domainNames)
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	resp := &domain.Response{}
+	for _, name := range domainNames {
+		resp.Data = append(resp.Data, domain.DomainData{Domain: name, Available: true})
+	}
+	return resp, nil
+}
+
+func (c *countingClient) SuggestDomains(domainName string) (*domain.Response, error) {
+	return c.SuggestDomainsContext(context.Background(), domainName)
+}
+
+func (c *countingClient) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	c.suggests = append(c.suggests, domainName)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &domain.Response{Data: []domain.DomainData{{Domain: domainName + ".com", Available: true}}}, nil
+}
+
+func newTestCache(t *testing.T, next *countingClient, availabilityTTL, suggestionTTL time.Duration) *Client {
+	t.Helper()
+
+	c, err := New(next, filepath.Join(t.TempDir(), "cache"), availabilityTTL, suggestionTTL)
+	if err != nil {
+		t.Fatalf("Failed to create cache: %v", err)
+	}
+	return c
+}
+
+func TestClient_CheckAvailabilityCached(t *testing.T) {
+	next := &countingClient{}
+	c := newTestCache(t, next, time.Hour, time.Hour)
+
+	before := time.Now()
+	first, err := c.CheckAvailability("Example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if first.Data[0].Cached || !first.Data[0].CachedAt.IsZero() {
+		t.Error("First lookup should not be marked as cached")
+	}
+
+	second, err := c.CheckAvailability("example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !second.Data[0].Cached {
+		t.Error("Second lookup should be marked as cached")
+	}
+	if at := second.Data[0].CachedAt; at.Before(before) || at.After(time.Now()) {
+		t.Errorf("Expected the time the entry was stored, got %v", at)
+	}
+
+	if len(next.checks) != 1 {
+		t.Errorf("Expected 1 upstream call, got %d", len(next.checks))
+	}
+}
+
+func TestClient_BatchOnlyFetchesMisses(t *testing.T) {
+	next := &countingClient{}
+	c := newTestCache(t, next, time.Hour, time.Hour)
+
+	if _, err := c.CheckAvailability("b.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result, err := c.CheckAvailabilityBatch([]string{"a.com", "b.com", "c.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(next.checks) != 2 || len(next.checks[1]) != 2 {
+		t.Fatalf("Expected second upstream call for 2 misses, got %v", next.checks)
+	}
+
+	expected := []string{"a.com", "b.com", "c.com"}
+	for i, name := range expected {
+		if result.Data[i].Domain != name {
+			t.Errorf("Result %d: expected %s, got %s", i, name, result.Data[i].Domain)
+		}
+	}
+	if !result.Data[1].Cached || result.Data[0].Cached {
+		t.Errorf("Expected only b.com to be cached, got %+v", result.Data)
+	}
+}
+
+func TestClient_Expiry(t *testing.T) {
+	next := &countingClient{}
+	c := newTestCache(t, next, time.Millisecond, time.Hour)
+
+	_, _ = c.CheckAvailability("example.com")
+	time.Sleep(5 * time.Millisecond)
+	_, _ = c.CheckAvailability("example.com")
+
+	if len(next.checks) != 2 {
+		t.Errorf("Expected expired entry to be refetched, got %d upstream calls", len(next.checks))
+	}
+
+	_, _ = c.SuggestDomains("example")
+	time.Sleep(5 * time.Millisecond)
+	_, _ = c.SuggestDomains("example")
+
+	if len(next.suggests) != 1 {
+		t.Errorf("Expected suggestions to use their own TTL, got %d upstream calls", len(next.suggests))
+	}
+}
+
+func TestClient_Refresh(t *testing.T) {
+	next := &countingClient{}
+	c := newTestCache(t, next, time.Hour, time.Hour)
+
+	_, _ = c.CheckAvailability("example.com")
+	resp, err := c.CheckAvailabilityContext(WithRefresh(context.Background()), "example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(next.checks) != 2 {
+		t.Errorf("Expected refresh to bypass the cache, got %d upstream calls", len(next.checks))
+	}
+	if resp.Data[0].Cached {
+		t.Error("Refreshed result should not be marked as cached")
+	}
+}
+
+func TestClient_ErrorsAreNotCached(t *testing.T) {
+	next := &countingClient{err: errors.New("network error")}
+	c := newTestCache(t, next, time.Hour, time.Hour)
+
+	if _, err := c.SuggestDomains("example"); err == nil {
+		t.Fatal("Expected error but got none")
+	}
+
+	next.err = nil
+	resp, err := c.SuggestDomains("example")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if resp.Data[0].Cached {
+		t.Error("Failed lookups must not be cached")
+	}
+}
+
+func TestClient_StatsAndClear(t *testing.T) {
+	next := &countingClient{}
+	c := newTestCache(t, next, time.Hour, time.Hour)
+
+	_, _ = c.CheckAvailabilityBatch([]string{"a.com", "b.com"})
+	_, _ = c.CheckAvailability("a.com")
+	_, _ = c.SuggestDomains("example")
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if stats.Entries != 3 || stats.Hits != 1 || stats.Misses != 3 || stats.Size == 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	files, _ := os.ReadDir(c.dir)
+	if len(files) != 0 {
+		t.Errorf("Expected empty cache directory, got %d files", len(files))
+	}
+
+	stats, _ = c.Stats()
+	if stats.Entries != 0 || stats.Hits != 0 {
+		t.Errorf("Expected empty stats after clear, got %+v", stats)
+	}
+}
+
+func TestClient_Private(t *testing.T) {
+	next := &countingClient{}
+	c := newTestCache(t, next, time.Hour, time.Hour)
+
+	_, _ = c.CheckAvailability("public.com")
+	c.SetPrivate(true)
+
+	resp, _ := c.CheckAvailability("public.com")
+	if len(next.checks) != 1 || !resp.Data[0].Cached {
+		t.Error("Expected private mode to still read the disk cache")
+	}
+
+	_, _ = c.CheckAvailability("secret.com")
+	_, _ = c.SuggestDomains("secret")
+	resp, _ = c.CheckAvailability("secret.com")
+	if len(next.checks) != 2 || !resp.Data[0].Cached {
+		t.Errorf("Expected private lookups to be cached in memory, got %d calls", len(next.checks))
+	}
+
+	files, _ := os.ReadDir(c.dir)
+	if len(files) != 1 {
+		t.Errorf("Expected private lookups to stay off disk, got %d files", len(files))
+	}
+
+	c.SetPrivate(false)
+	_, _ = c.CheckAvailability("secret.com")
+	if len(next.checks) != 3 {
+		t.Error("Expected the in-memory cache to be dropped when private mode ends")
+	}
+}
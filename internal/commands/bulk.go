@@ -0,0 +1,333 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"domainshell/internal/api"
+	"domainshell/internal/output"
+	"domainshell/pkg/domain"
+)
+
+const bulkWorkers = 4
+
+var errNoData = errors.New("no data returned")
+
+type lookupResult struct {
+	name string
+	item domain.DomainData
+	err  error
+}
+
+func (c *Commands) lookupAll(ctx context.Context, names []string, workers int, progress func(done, total int)) []lookupResult {
+	results := make([]lookupResult, len(names))
+	for i, name := range names {
+		results[i].name = name
+	}
+
+	var chunks [][]int
+	for start := 0; start < len(names); start += api.MaxBatchSize {
+		chunk := make([]int, 0, api.MaxBatchSize)
+		for i := start; i < min(start+api.MaxBatchSize, len(names)); i++ {
+			chunk = append(chunk, i)
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	if workers <= 0 {
+		workers = bulkWorkers
+	}
+	jobs := make(chan []int)
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+	for range min(workers, len(chunks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				c.lookupChunk(ctx, results, chunk)
+				if progress != nil {
+					mu.Lock()
+					done += len(chunk)
+					progress(done, len(names))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for _, chunk := range chunks {
+		jobs <- chunk
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+func (c *Commands) lookupChunk(ctx context.Context, results []lookupResult, chunk []int) {
+	var remaining []int
+	for _, i := range chunk {
+		if item, ok := c.precheck(ctx, results[i].name); ok {
+			results[i].item = item
+			continue
+		}
+		remaining = append(remaining, i)
+	}
+	if len(remaining) > 0 {
+		c.lookupBatch(ctx, results, remaining)
+	}
+}
+
+func (c *Commands) lookupBatch(ctx context.Context, results []lookupResult, remaining []int) {
+	names := make([]string, len(remaining))
+	for j, i := range remaining {
+		names[j] = results[i].name
+	}
+
+	resp, err := c.apiClient.CheckAvailabilityBatchContext(ctx, names)
+	if err != nil {
+		if len(remaining) == 1 || !errors.Is(err, api.ErrInvalidDomain) {
+			for _, i := range remaining {
+				results[i].err = err
+			}
+			return
+		}
+		for _, i := range remaining {
+			c.lookupBatch(ctx, results, []int{i})
+		}
+		return
+	}
+
+	found := make(map[string]domain.DomainData, len(resp.Data))
+	for _, item := range resp.Data {
+		found[strings.ToLower(item.Domain)] = item
+	}
+	for _, i := range remaining {
+		item, ok := found[strings.ToLower(results[i].name)]
+		if !ok {
+			results[i].err = errNoData
+			continue
+		}
+		results[i].item = item
+	}
+}
+
+func sortByAvailability(items []domain.DomainData) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Available != b.Available {
+			return a.Available
+		}
+		pa, pb := a.Prices.Register.OneYear, b.Prices.Register.OneYear
+		if (pa == 0) != (pb == 0) {
+			return pb == 0
+		}
+		if pa != pb {
+			return pa < pb
+		}
+		return a.Domain < b.Domain
+	})
+}
+
+const checkFileUsage = "check-file <path> [--column name]"
+
+func (c *Commands) SetInput(r io.Reader) {
+	c.stdin = r
+}
+
+func (c *Commands) SetProgress(w io.Writer) {
+	c.progress = w
+}
+
+func (c *Commands) runCheckFile(ctx context.Context, args string) error {
+	path, column, ok := parseFileArgs(args)
+	if !ok {
+		return c.usage(checkFileUsage)
+	}
+	return c.CheckFile(ctx, path, column)
+}
+
+func parseFileArgs(args string) (path, column string, ok bool) {
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "--column" && i+1 < len(fields):
+			column = fields[i+1]
+			i++
+		case strings.HasPrefix(field, "--column="):
+			column = strings.TrimPrefix(field, "--column=")
+		case path == "" && (field == "-" || !strings.HasPrefix(field, "-")):
+			path = field
+		default:
+			return "", "", false
+		}
+	}
+	return path, column, path != ""
+}
+
+func (c *Commands) CheckFile(ctx context.Context, path, column string) error {
+	if !api.Supports(c.apiClient, api.CapabilityCheck) {
+		return c.fail(fmt.Errorf("availability checks are %w", api.ErrUnsupported))
+	}
+
+	var r io.Reader
+	if path == "-" {
+		r = c.stdin
+		if r == nil {
+			r = os.Stdin
+		}
+	} else {
+		f, err := os.Open(path)
+		if err != nil {
+			return c.fail(err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	names, err := ReadDomains(r, column)
+	if err != nil {
+		return c.fail(err)
+	}
+	if len(names) == 0 {
+		return c.fail(fmt.Errorf("no domains found in %s", path))
+	}
+
+	return c.bulkCheck(ctx, names)
+}
+
+func (c *Commands) bulkCheck(ctx context.Context, names []string) error {
+	var progress func(done, total int)
+	if c.progress != nil {
+		progress = func(done, total int) {
+			fmt.Fprintf(c.progress, "\rChecked %d/%d", done, total)
+		}
+	}
+
+	results := c.lookupAll(ctx, names, bulkWorkers, progress)
+	if c.progress != nil {
+		fmt.Fprint(c.progress, "\r\033[K")
+	}
+	if err := ctx.Err(); err != nil {
+		return c.fail(err)
+	}
+
+	items, failures, summary := summarize(results)
+
+	c.outcome = OutcomeAvailable
+	if summary.Taken > 0 {
+		c.outcome = OutcomeTaken
+	}
+	if summary.Errors > 0 {
+		c.outcome = OutcomeError
+	}
+
+	c.record(items...)
+	c.out().Batch(items, failures, summary)
+
+	return nil
+}
+
+func summarize(results []lookupResult) ([]domain.DomainData, []output.Failure, output.Summary) {
+	items := make([]domain.DomainData, 0, len(results))
+	var failures []output.Failure
+	summary := output.Summary{Total: len(results)}
+	for _, result := range results {
+		if result.err != nil {
+			failures = append(failures, output.Failure{
+				Domain: result.name,
+				Err:    &displayError{message: describeError(result.err), err: result.err},
+			})
+			continue
+		}
+		items = append(items, result.item)
+		if result.item.Available {
+			summary.Available++
+		} else {
+			summary.Taken++
+		}
+	}
+	summary.Errors = len(failures)
+	return items, failures, summary
+}
+
+func ReadDomains(r io.Reader, column string) ([]string, error) {
+	var names []string
+	if column != "" {
+		var err error
+		if names, err = readColumn(r, column); err != nil {
+			return nil, err
+		}
+	} else {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			names = append(names, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[string]bool, len(names))
+	domains := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || strings.HasPrefix(name, "#") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		domains = append(domains, name)
+	}
+	return domains, nil
+}
+
+func readColumn(r io.Reader, column string) ([]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	index := -1
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		return nil, fmt.Errorf("column %q not found (have: %s)", column, strings.Join(header, ", "))
+	}
+
+	var names []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if index < len(record) {
+			names = append(names, record[index])
+		}
+	}
+}
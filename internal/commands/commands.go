@@ -1,16 +1,102 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 
 	"domainshell/internal/api"
+	"domainshell/internal/cache"
+	"domainshell/internal/config"
+	"domainshell/internal/currency"
+	"domainshell/internal/dnscheck"
+	"domainshell/internal/output"
+	"domainshell/internal/results"
+	"domainshell/internal/whois"
+	"domainshell/pkg/domain"
 )
 
+var (
+	ErrUnknownCommand = errors.New("unknown command")
+	ErrUsage          = errors.New("invalid usage")
+	ErrInteractive    = errors.New("only available in the interactive shell")
+)
+
+type Outcome int
+
+const (
+	OutcomeNone Outcome = iota
+	OutcomeAvailable
+	OutcomeTaken
+	OutcomeError
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeAvailable:
+		return "available"
+	case OutcomeTaken:
+		return "taken"
+	case OutcomeError:
+		return "error"
+	default:
+		return ""
+	}
+}
+
+func (o Outcome) ExitCode() int {
+	switch o {
+	case OutcomeTaken:
+		return 1
+	case OutcomeError:
+		return 2
+	default:
+		return 0
+	}
+}
+
+const defaultPriceYears = 5
+
+type WhoisLookup interface {
+	Lookup(ctx context.Context, domainName string) (*whois.Record, error)
+}
+
+type DNSChecker interface {
+	Registered(ctx context.Context, domainName string) (bool, error)
+}
+
 type Commands struct {
-	apiClient api.ClientInterface
+	apiClient   api.ClientInterface
+	whois       WhoisLookup
+	dns         DNSChecker
+	dnsPrecheck bool
+	renderer    output.Renderer
+	format      output.Format
+	prices      output.PriceFormatter
+	rates       *currency.Source
+	currency    currency.Code
+	locale      currency.Locale
+	settings    *config.Config
+	configPath  string
+	registry    *Registry
+	domains     func() []string
+	tldFile     string
+	stdin       io.Reader
+	progress    io.Writer
+	session     *results.Session
+	store       *results.Store
+	storeFailed bool
+	private     bool
+	unsaved     []results.Result
+	provider    string
+	outcome     Outcome
 }
 
 func NewCommands(apiClient api.ClientInterface) *Commands {
@@ -19,134 +105,779 @@ func NewCommands(apiClient api.ClientInterface) *Commands {
 	}
 }
 
-func formatPrice(price int) string {
-	if price >= 1000000 {
-		return fmt.Sprintf("%.2fM", float64(price)/1000000)
-	} else if price >= 1000 {
-		return fmt.Sprintf("%.1fK", float64(price)/1000)
+func (c *Commands) LastOutcome() Outcome {
+	return c.outcome
+}
+
+func (c *Commands) Run(ctx context.Context, command, args string) error {
+	c.outcome = OutcomeNone
+
+	cmd, ok := c.Registry().Lookup(command)
+	if !ok {
+		c.outcome = OutcomeError
+		return fmt.Errorf("%w: %s", ErrUnknownCommand, command)
+	}
+	return cmd.Run(ctx, args)
+}
+
+func (c *Commands) Registry() *Registry {
+	if c.registry == nil {
+		c.registry = NewRegistry()
+		c.registerBuiltins()
 	}
-	return fmt.Sprintf("%d", price)
+	return c.registry
+}
+
+func (c *Commands) Register(cmd Command) {
+	c.Registry().Register(cmd)
+}
+
+func (c *Commands) SetDomainSource(domains func() []string) {
+	c.domains = domains
 }
 
-func (c *Commands) Search(domainName string) error {
-	white := color.New(color.FgWhite)
-	green := color.New(color.FgGreen, color.Bold)
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
+func (c *Commands) registerBuiltins() {
+	domainArg := WithCompleter(func(args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		return c.knownDomains()
+	})
+
+	c.registry.Register(NewCommand("search", "search <domain>", "Check domain availability",
+		c.singleArg("search <domain>", c.Search), domainArg))
+	c.registry.Register(NewCommand("check", "check <domains...>", "Check several domains in one request (- reads them from stdin)",
+		func(ctx context.Context, args string) error {
+			if args == "" {
+				return c.usage("check <domain> [domain...]")
+			}
+			if args == "-" || strings.HasPrefix(args, "- ") {
+				path, column, ok := parseFileArgs(args)
+				if !ok {
+					return c.usage("check - [--column name]")
+				}
+				return c.CheckFile(ctx, path, column)
+			}
+			return c.Check(ctx, strings.Fields(args))
+		},
+		WithCompleter(func(args []string) []string { return c.knownDomains() })))
+	c.registry.Register(NewCommand("tlds", "tlds <label> [--set s]", "Check one name across a set of TLDs (popular, iran, all or a,b,c)",
+		c.runTLDs, WithCompleter(c.completeTLDs)))
+	c.registry.Register(NewCommand("check-file", checkFileUsage, "Check every domain in a text or CSV file",
+		c.runCheckFile))
+	c.registry.Register(NewCommand("suggest", "suggest <domain>", "Get domain suggestions",
+		c.singleArg("suggest <domain>", c.Suggest), domainArg))
+	c.registry.Register(NewCommand("whois", "whois <domain>", "Show registrar, dates and name servers",
+		c.singleArg("whois <domain>", c.Whois), domainArg))
+	c.registry.Register(NewCommand("price", "price <domain> [n]", "Show all prices and the cost of owning it for n years",
+		c.runPrice, domainArg))
+	c.registry.Register(NewCommand("refresh", "refresh <domain>", "Check availability, bypassing the cache",
+		c.singleArg("refresh <domain>", func(ctx context.Context, args string) error {
+			return c.Search(cache.WithRefresh(ctx), args)
+		}), domainArg))
+	c.registry.Register(NewCommand("results", resultsUsage, "List saved lookups without touching the network",
+		c.runResults, WithCompleter(func(args []string) []string {
+			if len(args) == 0 {
+				return append([]string{"show", "clear"}, resultFlags...)
+			}
+			if args[0] == "show" {
+				return c.knownDomains()
+			}
+			return resultFlags
+		})))
+	c.registry.Register(NewCommand("export", exportUsage, "Write this session's results as CSV, JSON or Markdown",
+		c.runExport, WithCompleter(fixedArgs("csv", "json", "markdown"))))
+	c.registry.Register(NewCommand("cache", "cache stats|clear", "Show or clear the response cache",
+		func(ctx context.Context, args string) error { return c.Cache(args) },
+		WithCompleter(fixedArgs("stats", "clear"))))
+	c.registry.Register(NewCommand("set", "set <key> <value>", "Change a setting (output, dns, currency, locale, prompt, ...) and save it",
+		func(ctx context.Context, args string) error { return c.Set(ctx, args) },
+		WithCompleter(c.completeSetting)))
+	c.registry.Register(NewCommand("get", "get <key>", "Show the current value of a setting",
+		func(ctx context.Context, args string) error { return c.Get(args) },
+		WithCompleter(fixedArgs(settingKeys()...))))
+	c.registry.Register(NewCommand("config", "config show", "Show every setting",
+		func(ctx context.Context, args string) error { return c.ShowConfig(args) },
+		WithCompleter(fixedArgs("show"))))
+	c.registry.Register(NewCommand("history", "history", "Show command history", c.interactiveOnly("history")))
+	c.registry.Register(NewCommand("incognito", "incognito [on|off]", "Keep history, cache and results in memory only", c.interactiveOnly("incognito")))
+	c.registry.Register(NewCommand("help", "help [command]", "Show this help message",
+		func(ctx context.Context, args string) error { return c.Help(args) },
+		WithCompleter(func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return c.Registry().Names()
+		})))
+	c.registry.Register(NewCommand("exit", "exit, quit", "Exit the program", c.interactiveOnly("exit"), WithAliases("quit")))
+}
+
+func (c *Commands) singleArg(usage string, run RunFunc) RunFunc {
+	return func(ctx context.Context, args string) error {
+		fields := strings.Fields(args)
+		if len(fields) != 1 {
+			return c.usage(usage)
+		}
+		return run(ctx, fields[0])
+	}
+}
+
+func (c *Commands) interactiveOnly(name string) RunFunc {
+	return func(ctx context.Context, args string) error {
+		c.outcome = OutcomeError
+		return fmt.Errorf("%s is %w", name, ErrInteractive)
+	}
+}
+
+func (c *Commands) runPrice(ctx context.Context, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || len(fields) > 2 {
+		return c.usage("price <domain> [years]")
+	}
+	years := defaultPriceYears
+	if len(fields) == 2 {
+		n, err := strconv.Atoi(fields[1])
+		if err != nil || n < 1 || n > domain.MaxYears {
+			return c.usage(fmt.Sprintf("price <domain> [years], years between 1 and %d", domain.MaxYears))
+		}
+		years = n
+	}
+	return c.Price(ctx, fields[0], years)
+}
+
+func (c *Commands) knownDomains() []string {
+	if c.domains == nil {
+		return nil
+	}
+	return c.domains()
+}
+
+func fixedArgs(values ...string) CompleteFunc {
+	return func(args []string) []string {
+		if len(args) > 0 {
+			return nil
+		}
+		return values
+	}
+}
+
+func settingKeys() []string {
+	keys := config.Keys()
+	for alias := range settingAliases {
+		keys = append(keys, alias)
+	}
+	return keys
+}
+
+func (c *Commands) completeSetting(args []string) []string {
+	switch len(args) {
+	case 0:
+		return settingKeys()
+	case 1:
+		key := strings.ToLower(args[0])
+		if alias, ok := settingAliases[key]; ok {
+			key = alias
+		}
+		switch key {
+		case "output":
+			values := make([]string, len(output.Formats))
+			for i, f := range output.Formats {
+				values[i] = string(f)
+			}
+			return values
+		case "currency":
+			values := make([]string, len(currency.Codes))
+			for i, code := range currency.Codes {
+				values[i] = string(code)
+			}
+			return values
+		case "locale":
+			return []string{string(currency.LocaleEnglish), string(currency.LocalePersian)}
+		case "cache", "dns_precheck":
+			return []string{"on", "off"}
+		}
+	}
+	return nil
+}
+
+func (c *Commands) Fail(err error) error {
+	return c.fail(err)
+}
 
-	result, err := c.apiClient.CheckAvailability(domainName)
+func (c *Commands) Usage(usage string) error {
+	return c.usage(usage)
+}
+
+func (c *Commands) Output() output.Renderer {
+	return c.out()
+}
+
+func (c *Commands) fail(err error) error {
+	c.outcome = OutcomeError
+	c.out().Error(&displayError{message: describeError(err), err: err})
+	return err
+}
+
+type displayError struct {
+	message string
+	err     error
+}
+
+func (e *displayError) Error() string { return e.message }
+
+func (e *displayError) Unwrap() error { return e.err }
+
+func describeError(err error) string {
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) {
+		switch {
+		case errors.Is(err, context.Canceled):
+			return "request cancelled"
+		case errors.Is(err, context.DeadlineExceeded):
+			return "request timed out"
+		}
+		return err.Error()
+	}
+
+	var msg string
+	switch {
+	case errors.Is(err, api.ErrRateLimited):
+		msg = "rate limited by the API, wait a moment and try again"
+	case errors.Is(err, api.ErrServerError):
+		msg = fmt.Sprintf("the API is having trouble (HTTP %d), try again later", apiErr.StatusCode)
+	case errors.Is(err, api.ErrInvalidDomain):
+		msg = "invalid domain name"
+	case errors.Is(err, api.ErrMalformedResponse):
+		msg = fmt.Sprintf("unexpected response from the API (HTTP %d)", apiErr.StatusCode)
+	default:
+		msg = fmt.Sprintf("unexpected API response (HTTP %d)", apiErr.StatusCode)
+	}
+
+	if apiErr.Message != "" {
+		msg += ": " + apiErr.Message
+	}
+	return msg
+}
+
+func (c *Commands) usage(usage string) error {
+	c.outcome = OutcomeError
+	err := &displayError{message: "usage: " + usage, err: ErrUsage}
+	c.out().Error(err)
+	return err
+}
+
+func (c *Commands) Search(ctx context.Context, domainName string) error {
+	if !api.Supports(c.apiClient, api.CapabilityCheck) {
+		return c.fail(fmt.Errorf("availability checks are %w", api.ErrUnsupported))
+	}
+
+	if item, ok := c.precheck(ctx, domainName); ok {
+		c.outcome = OutcomeTaken
+		c.record(item)
+		c.out().Availability([]domain.DomainData{item})
+		return nil
+	}
+
+	result, err := c.apiClient.CheckAvailabilityContext(ctx, domainName)
 	if err != nil {
-		red.Printf("Request error: %v\n", err)
-		return err
+		return c.fail(err)
 	}
 
 	if len(result.Data) == 0 {
-		yellow.Println("No data returned")
+		c.outcome = OutcomeError
+		c.out().Availability(nil)
 		return nil
 	}
 
-	item := result.Data[0]
-	if item.Available {
-		green.Printf("%s is available", item.Domain)
-		if item.Prices.Register.OneYear > 0 {
-			white.Printf(" (%s Toman/year)", formatPrice(item.Prices.Register.OneYear))
+	c.outcome = outcomeOf(result.Data[0])
+	c.record(result.Data[0])
+	c.out().Availability(result.Data[:1])
+
+	return nil
+}
+
+func (c *Commands) Check(ctx context.Context, domainNames []string) error {
+	if !api.Supports(c.apiClient, api.CapabilityCheck) {
+		return c.fail(fmt.Errorf("availability checks are %w", api.ErrUnsupported))
+	}
+
+	found := make(map[string]domain.DomainData, len(domainNames))
+	var remaining []string
+	for _, name := range domainNames {
+		if item, ok := c.precheck(ctx, name); ok {
+			found[strings.ToLower(name)] = item
+			continue
 		}
-		if item.OnSale {
-			yellow.Print(" [ON SALE]")
+		remaining = append(remaining, name)
+	}
+
+	if len(remaining) > 0 {
+		result, err := c.apiClient.CheckAvailabilityBatchContext(ctx, remaining)
+		if err != nil {
+			return c.fail(err)
 		}
-		if item.Premium {
-			yellow.Print(" [PREMIUM]")
+		for _, item := range result.Data {
+			found[strings.ToLower(item.Domain)] = item
 		}
-		fmt.Println()
-	} else {
-		red.Printf("%s is NOT available", item.Domain)
-		if item.Reason != "" {
-			yellow.Printf(" (%s)", item.Reason)
+	}
+
+	results := make([]lookupResult, len(domainNames))
+	for i, name := range domainNames {
+		results[i].name = name
+		item, ok := found[strings.ToLower(name)]
+		if !ok {
+			results[i].err = errNoData
+			continue
 		}
-		fmt.Println()
+		results[i].item = item
 	}
+	items, failures, summary := summarize(results)
 
+	c.outcome = OutcomeAvailable
+	for _, item := range items {
+		c.outcome = max(c.outcome, outcomeOf(item))
+	}
+	if len(failures) > 0 {
+		c.outcome = OutcomeError
+	}
+
+	c.record(items...)
+	c.out().Batch(items, failures, summary)
 	return nil
 }
 
-func (c *Commands) Suggest(domainName string) error {
-	green := color.New(color.FgGreen, color.Bold)
-	red := color.New(color.FgRed, color.Bold)
-	yellow := color.New(color.FgYellow)
-	white := color.New(color.FgWhite)
+func (c *Commands) SetDNSChecker(checker DNSChecker) {
+	c.dns = checker
+}
+
+func (c *Commands) EnableDNSPrecheck(enabled bool) {
+	c.dnsPrecheck = enabled
+	if enabled && c.dns == nil {
+		c.dns = dnscheck.NewChecker(dnscheck.DefaultResolver, 0)
+	}
+}
+
+func (c *Commands) precheck(ctx context.Context, domainName string) (domain.DomainData, bool) {
+	if !c.dnsPrecheck || c.dns == nil {
+		return domain.DomainData{}, false
+	}
+
+	registered, err := c.dns.Registered(ctx, domainName)
+	if err != nil || !registered {
+		return domain.DomainData{}, false
+	}
+	return dnscheck.Result(domainName, true), true
+}
 
-	result, err := c.apiClient.SuggestDomains(domainName)
+func outcomeOf(item domain.DomainData) Outcome {
+	if item.Available {
+		return OutcomeAvailable
+	}
+	return OutcomeTaken
+}
+
+func (c *Commands) Suggest(ctx context.Context, domainName string) error {
+	if !api.Supports(c.apiClient, api.CapabilitySuggest) {
+		return c.fail(fmt.Errorf("suggestions are %w", api.ErrUnsupported))
+	}
+
+	result, err := c.apiClient.SuggestDomainsContext(ctx, domainName)
 	if err != nil {
-		red.Printf("Request error: %v\n", err)
-		return err
+		return c.fail(err)
+	}
+
+	c.record(result.Data...)
+	c.out().Suggestions(domainName, result.Data)
+
+	return nil
+}
+
+func (c *Commands) Price(ctx context.Context, domainName string, years int) error {
+	if !api.Supports(c.apiClient, api.CapabilityCheck|api.CapabilityPricing) {
+		return c.fail(fmt.Errorf("pricing is %w", api.ErrUnsupported))
 	}
 
+	result, err := c.apiClient.CheckAvailabilityContext(ctx, domainName)
+	if err != nil {
+		return c.fail(err)
+	}
 	if len(result.Data) == 0 {
-		yellow.Println("No suggestions found")
+		c.outcome = OutcomeError
+		c.out().Availability(nil)
 		return nil
 	}
 
-	white.Printf("Suggestions for %s:\n", domainName)
-	for _, item := range result.Data {
-		if item.Available {
-			green.Printf("  %s", item.Domain)
-			if item.Prices.Register.OneYear > 0 {
-				white.Printf(" (%s Toman/year)", formatPrice(item.Prices.Register.OneYear))
-			}
-			if item.OnSale {
-				yellow.Print(" [ON SALE]")
+	item := result.Data[0]
+	c.outcome = outcomeOf(item)
+	c.record(item)
+	if item.Prices.Register.OneYear == 0 {
+		c.out().Availability([]domain.DomainData{item})
+		return nil
+	}
+
+	c.out().Fields(fmt.Sprintf("Price of %s over %d years", item.Domain, years), priceFields(item, years))
+	return nil
+}
+
+func priceFields(item domain.DomainData, years int) []output.Field {
+	prices := item.Prices
+	fields := []output.Field{
+		{Key: "domain", Value: item.Domain},
+		{Key: "available", Value: item.Available},
+		{Key: "register", Value: output.Price(prices.Register.OneYear)},
+	}
+	if prices.Renew.OneYear > 0 {
+		fields = append(fields, output.Field{Key: "renew", Value: output.Price(prices.Renew.OneYear)})
+	}
+	if prices.Transfer.OneYear > 0 {
+		fields = append(fields, output.Field{Key: "transfer", Value: output.Price(prices.Transfer.OneYear)})
+	}
+	if prices.Restore.OneYear > 0 {
+		fields = append(fields, output.Field{Key: "restore", Value: output.Price(prices.Restore.OneYear)})
+	}
+	if upfront := prices.Register.For(years); years > 1 && upfront > 0 {
+		fields = append(fields, output.Field{Key: fmt.Sprintf("register_%dy", years), Value: output.Price(upfront)})
+	}
+	fields = append(fields,
+		output.Field{Key: "years", Value: years},
+		output.Field{Key: "total", Value: output.Price(prices.TotalCost(years))},
+	)
+	if renew := prices.RenewalPrice(); renew > prices.Register.OneYear {
+		increase := (renew - prices.Register.OneYear) * 100 / prices.Register.OneYear
+		fields = append(fields, output.Field{Key: "note", Value: fmt.Sprintf("renewals cost %d%% more than the first year", increase)})
+	}
+	return fields
+}
+
+func (c *Commands) SetWhois(lookup WhoisLookup) {
+	c.whois = lookup
+}
+
+func (c *Commands) Whois(ctx context.Context, domainName string) error {
+	if c.whois == nil {
+		c.whois = whois.NewClient()
+	}
+
+	record, err := c.whois.Lookup(ctx, domainName)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.outcome = OutcomeTaken
+	if record.Available {
+		c.outcome = OutcomeAvailable
+	}
+
+	fields := []output.Field{
+		{Key: "domain", Value: record.Domain},
+		{Key: "available", Value: record.Available},
+		{Key: "server", Value: record.Server},
+	}
+	if record.Registrar != "" {
+		fields = append(fields, output.Field{Key: "registrar", Value: record.Registrar})
+	}
+	for _, date := range []struct {
+		key  string
+		date *time.Time
+	}{
+		{"created", record.Created},
+		{"updated", record.Updated},
+		{"expires", record.Expires},
+	} {
+		if date.date != nil {
+			fields = append(fields, output.Field{Key: date.key, Value: *date.date})
+		}
+	}
+	if len(record.NameServers) > 0 {
+		fields = append(fields, output.Field{Key: "name_servers", Value: record.NameServers})
+	}
+	if len(record.Status) > 0 {
+		fields = append(fields, output.Field{Key: "status", Value: record.Status})
+	}
+
+	c.out().Fields("WHOIS "+record.Domain, fields)
+	return nil
+}
+
+type cacheManager interface {
+	Stats() (cache.Stats, error)
+	Clear() error
+}
+
+func (c *Commands) Cache(args string) error {
+	cm, ok := c.apiClient.(cacheManager)
+	if !ok {
+		return c.fail(errors.New("cache is disabled"))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "", "stats":
+		stats, err := cm.Stats()
+		if err != nil {
+			return c.fail(err)
+		}
+		c.out().Fields("Cache", []output.Field{
+			{Key: "entries", Value: stats.Entries},
+			{Key: "expired", Value: stats.Expired},
+			{Key: "size_bytes", Value: stats.Size},
+			{Key: "hits", Value: stats.Hits},
+			{Key: "misses", Value: stats.Misses},
+		})
+	case "clear":
+		if err := cm.Clear(); err != nil {
+			return c.fail(err)
+		}
+		c.out().Message("Cache cleared")
+	default:
+		return c.usage("cache stats|clear")
+	}
+
+	return nil
+}
+
+var settingAliases = map[string]string{
+	"dns": "dns_precheck",
+}
+
+var liveSettings = map[string]bool{
+	"output":       true,
+	"dns_precheck": true,
+	"currency":     true,
+	"locale":       true,
+	"prompt":       true,
+	"history_size": true,
+	"history_view": true,
+}
+
+func (c *Commands) SetConfig(cfg config.Config, path string) {
+	c.settings = &cfg
+	c.configPath = path
+}
+
+func (c *Commands) Config() config.Config {
+	if c.settings == nil {
+		return config.Default()
+	}
+	return *c.settings
+}
+
+func (c *Commands) Set(ctx context.Context, args string) error {
+	key, value, _ := strings.Cut(strings.TrimSpace(args), " ")
+	value = strings.TrimSpace(value)
+	if key == "" || value == "" {
+		return c.usage("set <key> <value>")
+	}
+
+	key = strings.ToLower(key)
+	if alias, ok := settingAliases[key]; ok {
+		key = alias
+	}
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	settings := c.Config()
+	if err := settings.Set(key, value); err != nil {
+		return c.fail(err)
+	}
+	if err := c.apply(ctx, key, &settings); err != nil {
+		return c.fail(err)
+	}
+	c.settings = &settings
+
+	if err := c.persist(key); err != nil {
+		return c.fail(fmt.Errorf("failed to save config: %w", err))
+	}
+	if !liveSettings[key] {
+		c.out().Message(fmt.Sprintf("%s saved, it takes effect the next time domainshell starts", key))
+	}
+
+	return nil
+}
+
+func (c *Commands) apply(ctx context.Context, key string, settings *config.Config) error {
+	switch key {
+	case "output":
+		format, err := output.ParseFormat(settings.Output)
+		if err != nil {
+			return err
+		}
+		settings.Output = string(format)
+		c.SetOutputFormat(format)
+	case "dns_precheck":
+		c.EnableDNSPrecheck(settings.DNSPrecheck)
+	case "currency", "locale":
+		code := currency.IRT
+		if settings.Currency != "" {
+			var err error
+			if code, err = currency.ParseCode(settings.Currency); err != nil {
+				return err
 			}
-			if item.Premium {
-				yellow.Print(" [PREMIUM]")
+		}
+		var locale currency.Locale
+		if settings.Locale != "" {
+			var err error
+			if locale, err = currency.ParseLocale(settings.Locale); err != nil {
+				return err
 			}
-			fmt.Println()
 		}
+		if err := c.SetCurrency(ctx, code, locale); err != nil {
+			return err
+		}
+		settings.Currency = string(code)
+		settings.Locale = string(c.locale)
 	}
+	return nil
+}
 
+func (c *Commands) persist(key string) error {
+	if c.configPath == "" {
+		return nil
+	}
+
+	value, err := c.settings.Get(key)
+	if err != nil {
+		return err
+	}
+
+	saved, err := config.Load(c.configPath)
+	if err != nil {
+		return err
+	}
+	if err := saved.Set(key, value); err != nil {
+		return err
+	}
+	if key == "currency" || key == "locale" {
+		saved.Currency, saved.Locale = c.settings.Currency, c.settings.Locale
+	}
+	return saved.Save(c.configPath)
+}
+
+func (c *Commands) Get(args string) error {
+	key := strings.ToLower(strings.TrimSpace(args))
+	if alias, ok := settingAliases[key]; ok {
+		key = alias
+	}
+	if key == "" {
+		return c.usage("get <key>")
+	}
+
+	value, err := c.Config().Get(key)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.out().Fields("", []output.Field{{Key: key, Value: value}})
 	return nil
 }
 
-func ParseInput(input string) (command string, args string) {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return "", ""
+func (c *Commands) ShowConfig(args string) error {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "", "show":
+	default:
+		return c.usage("config show")
+	}
+
+	settings := c.Config()
+	fields := make([]output.Field, 0, len(config.Keys()))
+	for _, key := range config.Keys() {
+		value, err := settings.Get(key)
+		if err != nil {
+			return c.fail(err)
+		}
+		fields = append(fields, output.Field{Key: key, Value: value})
+	}
+
+	title := "Config"
+	if c.configPath != "" {
+		title += " (" + c.configPath + ")"
 	}
+	c.out().Fields(title, fields)
+	return nil
+}
 
+func (c *Commands) SetOutputFormat(format output.Format) {
+	w, errW := io.Writer(os.Stdout), io.Writer(os.Stderr)
+	if format == output.FormatTable {
+		w, errW = color.Output, color.Error
+	}
+	c.format = format
+	c.renderer = output.New(format, w, output.WithPriceFormatter(c.prices), output.WithErrorWriter(errW))
+}
+
+func (c *Commands) SetRates(source *currency.Source) {
+	c.rates = source
+}
+
+func (c *Commands) SetCurrency(ctx context.Context, code currency.Code, locale currency.Locale) error {
+	if locale == "" {
+		locale = c.locale
+	}
+
+	var rates currency.Rates
+	if !code.Fixed() {
+		if c.rates == nil {
+			return fmt.Errorf("%w for %s: no rates file or endpoint configured", currency.ErrNoRate, code)
+		}
+		var err error
+		if rates, err = c.rates.Rates(ctx); err != nil {
+			return err
+		}
+	}
+
+	formatter, err := currency.NewFormatter(code, locale, rates)
+	if err != nil {
+		return err
+	}
+
+	c.currency = code
+	c.locale = formatter.Locale()
+	c.prices = formatter
+	if c.format == "" {
+		c.format = output.FormatTable
+	}
+	c.SetOutputFormat(c.format)
+	return nil
+}
+
+func (c *Commands) out() output.Renderer {
+	if c.renderer == nil {
+		c.SetOutputFormat(output.FormatTable)
+	}
+	return c.renderer
+}
+
+func (c *Commands) ParseInput(input string) (command string, args string) {
 	parts := strings.Fields(input)
 	if len(parts) == 0 {
 		return "", ""
 	}
 
 	first := strings.ToLower(parts[0])
-	knownCommands := map[string]bool{
-		"search":  true,
-		"suggest": true,
-		"exit":    true,
-		"quit":    true,
-		"help":    true,
-		"history": true,
+	if _, ok := c.Registry().Lookup(first); ok {
+		return first, strings.Join(parts[1:], " ")
 	}
 
-	if knownCommands[first] {
-		if len(parts) > 1 {
-			return first, strings.Join(parts[1:], " ")
-		}
-		return first, ""
-	}
-
-	return "search", input
+	return "search", strings.Join(parts, " ")
 }
 
-func (c *Commands) Help() {
-	white := color.New(color.FgWhite)
-	cyan := color.New(color.FgCyan)
+func (c *Commands) Help(args string) error {
+	if name := strings.TrimSpace(args); name != "" {
+		cmd, ok := c.Registry().Lookup(name)
+		if !ok {
+			return c.fail(fmt.Errorf("%w: %s", ErrUnknownCommand, name))
+		}
+		c.out().Fields("", []output.Field{
+			{Key: "usage", Value: cmd.Usage()},
+			{Key: "summary", Value: cmd.Summary()},
+		})
+		return nil
+	}
 
-	cyan.Println("Available commands:")
-	white.Println("  <domain>           - Check domain availability (default)")
-	white.Println("  search <domain>    - Check domain availability")
-	white.Println("  suggest <domain>   - Get domain suggestions")
-	white.Println("  history            - Show command history")
-	white.Println("  help               - Show this help message")
-	white.Println("  exit, quit         - Exit the program")
-	fmt.Println()
+	fields := []output.Field{{Key: "<domain>", Value: "Check domain availability (default)"}}
+	for _, cmd := range c.Registry().Commands() {
+		fields = append(fields, output.Field{Key: cmd.Usage(), Value: cmd.Summary()})
+	}
+	c.out().Fields("Available commands", fields)
+	return nil
 }
@@ -1,15 +1,29 @@
 package commands
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"domainshell/internal/api"
+	"domainshell/internal/config"
+	"domainshell/internal/output"
+	"domainshell/internal/results"
+	"domainshell/internal/whois"
 	"domainshell/pkg/domain"
 )
 
 type mockAPIClient struct {
-	checkAvailabilityFunc func(string) (*domain.Response, error)
-	suggestDomainsFunc    func(string) (*domain.Response, error)
+	checkAvailabilityFunc      func(string) (*domain.Response, error)
+	checkAvailabilityBatchFunc func([]string) (*domain.Response, error)
+	suggestDomainsFunc         func(string) (*domain.Response, error)
 }
 
 func (m *mockAPIClient) CheckAvailability(domainName string) (*domain.Response, error) {
@@ -19,6 +33,21 @@ func (m *mockAPIClient) CheckAvailability(domainName string) (*domain.Response,
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockAPIClient) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return m.CheckAvailability(domainName)
+}
+
+func (m *mockAPIClient) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	if m.checkAvailabilityBatchFunc != nil {
+		return m.checkAvailabilityBatchFunc(domainNames)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAPIClient) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	return m.CheckAvailabilityBatch(domainNames)
+}
+
 func (m *mockAPIClient) SuggestDomains(domainName string) (*domain.Response, error) {
 	if m.suggestDomainsFunc != nil {
 		return m.suggestDomainsFunc(domainName)
@@ -26,6 +55,10 @@ func (m *mockAPIClient) SuggestDomains(domainName string) (*domain.Response, err
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockAPIClient) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return m.SuggestDomains(domainName)
+}
+
 func TestParseInput(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -69,6 +102,12 @@ func TestParseInput(t *testing.T) {
 			expectedCmd:  "suggest",
 			expectedArgs: "example domain",
 		},
+		{
+			name:         "check command",
+			input:        "check example.com example.net",
+			expectedCmd:  "check",
+			expectedArgs: "example.com example.net",
+		},
 		{
 			name:         "help command",
 			input:        "help",
@@ -103,7 +142,7 @@ func TestParseInput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd, args := ParseInput(tt.input)
+			cmd, args := NewCommands(nil).ParseInput(tt.input)
 			if cmd != tt.expectedCmd {
 				t.Errorf("Expected command %q, got %q", tt.expectedCmd, cmd)
 			}
@@ -114,49 +153,6 @@ func TestParseInput(t *testing.T) {
 	}
 }
 
-func TestFormatPrice(t *testing.T) {
-	tests := []struct {
-		name     string
-		price    int
-		expected string
-	}{
-		{
-			name:     "price less than 1000",
-			price:    500,
-			expected: "500",
-		},
-		{
-			name:     "price in thousands",
-			price:    5000,
-			expected: "5.0K",
-		},
-		{
-			name:     "price in millions",
-			price:    1500000,
-			expected: "1.50M",
-		},
-		{
-			name:     "exact thousand",
-			price:    1000,
-			expected: "1.0K",
-		},
-		{
-			name:     "exact million",
-			price:    1000000,
-			expected: "1.00M",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := formatPrice(tt.price)
-			if result != tt.expected {
-				t.Errorf("Expected %q, got %q", tt.expected, result)
-			}
-		})
-	}
-}
-
 func TestCommands_Search(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -175,14 +171,8 @@ func TestCommands_Search(t *testing.T) {
 						Domain:    "example.com",
 						OnSale:    false,
 						Premium:   false,
-						Prices: struct {
-							Register struct {
-								OneYear int `json:"1y"`
-							} `json:"register"`
-						}{
-							Register: struct {
-								OneYear int `json:"1y"`
-							}{
+						Prices: domain.Prices{
+							Register: domain.PeriodPrices{
 								OneYear: 100000,
 							},
 						},
@@ -236,7 +226,7 @@ func TestCommands_Search(t *testing.T) {
 				apiClient: mockClient,
 			}
 
-			err := cmds.Search(tt.domain)
+			err := cmds.Search(context.Background(), tt.domain)
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -298,7 +288,232 @@ func TestCommands_Suggest(t *testing.T) {
 				apiClient: mockClient,
 			}
 
-			err := cmds.Suggest(tt.domain)
+			err := cmds.Suggest(context.Background(), tt.domain)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommands_Check(t *testing.T) {
+	tests := []struct {
+		name        string
+		domains     []string
+		response    *domain.Response
+		apiError    error
+		expectError bool
+	}{
+		{
+			name:    "mixed availability",
+			domains: []string{"example.com", "example.net"},
+			response: &domain.Response{
+				Data: []domain.DomainData{
+					{Available: true, Domain: "example.com"},
+					{Available: false, Domain: "example.net", Reason: "Already registered"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:    "missing result",
+			domains: []string{"example.com", "example.net"},
+			response: &domain.Response{
+				Data: []domain.DomainData{
+					{Available: true, Domain: "example.com"},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name:        "api error",
+			domains:     []string{"example.com"},
+			apiError:    errors.New("network error"),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockAPIClient{
+				checkAvailabilityBatchFunc: func(domainNames []string) (*domain.Response, error) {
+					if len(domainNames) != len(tt.domains) {
+						t.Errorf("Expected %d domains, got %d", len(tt.domains), len(domainNames))
+					}
+					if tt.apiError != nil {
+						return nil, tt.apiError
+					}
+					return tt.response, nil
+				},
+			}
+
+			cmds := &Commands{
+				apiClient: mockClient,
+			}
+
+			err := cmds.Check(context.Background(), tt.domains)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCommands_Run(t *testing.T) {
+	mockClient := &mockAPIClient{
+		checkAvailabilityFunc: func(domainName string) (*domain.Response, error) {
+			switch domainName {
+			case "free.com":
+				return &domain.Response{Data: []domain.DomainData{{Available: true, Domain: domainName}}}, nil
+			case "taken.com":
+				return &domain.Response{Data: []domain.DomainData{{Available: false, Domain: domainName}}}, nil
+			}
+			return nil, errors.New("network error")
+		},
+		checkAvailabilityBatchFunc: func(domainNames []string) (*domain.Response, error) {
+			result := &domain.Response{}
+			for _, name := range domainNames {
+				result.Data = append(result.Data, domain.DomainData{Available: name == "free.com", Domain: name})
+			}
+			return result, nil
+		},
+		suggestDomainsFunc: func(domainName string) (*domain.Response, error) {
+			return &domain.Response{}, nil
+		},
+	}
+
+	tests := []struct {
+		name            string
+		command         string
+		args            string
+		expectedOutcome Outcome
+		expectedErr     error
+	}{
+		{
+			name:            "search available",
+			command:         "search",
+			args:            "free.com",
+			expectedOutcome: OutcomeAvailable,
+		},
+		{
+			name:            "search taken",
+			command:         "search",
+			args:            "taken.com",
+			expectedOutcome: OutcomeTaken,
+		},
+		{
+			name:            "search error",
+			command:         "search",
+			args:            "broken.com",
+			expectedOutcome: OutcomeError,
+			expectedErr:     errors.New("network error"),
+		},
+		{
+			name:            "check all available",
+			command:         "check",
+			args:            "free.com free.com",
+			expectedOutcome: OutcomeAvailable,
+		},
+		{
+			name:            "check some taken",
+			command:         "check",
+			args:            "free.com taken.com",
+			expectedOutcome: OutcomeTaken,
+		},
+		{
+			name:            "suggest",
+			command:         "suggest",
+			args:            "example",
+			expectedOutcome: OutcomeNone,
+		},
+		{
+			name:            "missing argument",
+			command:         "search",
+			expectedOutcome: OutcomeError,
+			expectedErr:     ErrUsage,
+		},
+		{
+			name:            "extra arguments",
+			command:         "search",
+			args:            "free.com -o json",
+			expectedOutcome: OutcomeError,
+			expectedErr:     ErrUsage,
+		},
+		{
+			name:            "unknown command",
+			command:         "frobnicate",
+			expectedOutcome: OutcomeError,
+			expectedErr:     ErrUnknownCommand,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := NewCommands(mockClient)
+
+			err := cmds.Run(context.Background(), tt.command, tt.args)
+			if tt.expectedErr == nil && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if tt.expectedErr != nil && err == nil {
+				t.Errorf("Expected error %v but got none", tt.expectedErr)
+			}
+			if cmds.LastOutcome() != tt.expectedOutcome {
+				t.Errorf("Expected outcome %v, got %v", tt.expectedOutcome, cmds.LastOutcome())
+			}
+		})
+	}
+}
+
+func TestOutcome_ExitCode(t *testing.T) {
+	tests := []struct {
+		outcome  Outcome
+		expected int
+	}{
+		{OutcomeNone, 0},
+		{OutcomeAvailable, 0},
+		{OutcomeTaken, 1},
+		{OutcomeError, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.outcome.String(), func(t *testing.T) {
+			if code := tt.outcome.ExitCode(); code != tt.expected {
+				t.Errorf("Expected exit code %d, got %d", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestCommands_Set(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        string
+		expectError bool
+	}{
+		{name: "json output", args: "output json"},
+		{name: "table output", args: "output TABLE"},
+		{name: "unknown format", args: "output xml", expectError: true},
+		{name: "unknown key", args: "colour blue", expectError: true},
+		{name: "missing value", args: "output", expectError: true},
+		{name: "currency rial", args: "currency irr"},
+		{name: "currency without rates", args: "currency usd", expectError: true},
+		{name: "unknown currency", args: "currency gbp", expectError: true},
+		{name: "persian locale", args: "locale fa"},
+		{name: "unknown locale", args: "locale de", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := NewCommands(&mockAPIClient{})
+
+			err := cmds.Set(context.Background(), tt.args)
 			if tt.expectError && err == nil {
 				t.Error("Expected error but got none")
 			}
@@ -309,9 +524,784 @@ func TestCommands_Suggest(t *testing.T) {
 	}
 }
 
+func TestCommands_SetPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(`{"provider": "rdap"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	effective := config.Default()
+	effective.Provider = "mock"
+	cmds := NewCommands(&mockAPIClient{})
+	cmds.SetConfig(effective, path)
+
+	for _, args := range []string{`prompt "> "`, "history_view 5", "dns on", "output JSON"} {
+		if err := cmds.Set(context.Background(), args); err != nil {
+			t.Fatalf("set %s: unexpected error: %v", args, err)
+		}
+	}
+
+	settings := cmds.Config()
+	if settings.Prompt != "> " || settings.HistoryView != 5 || !settings.DNSPrecheck || settings.Output != "json" {
+		t.Errorf("Unexpected session settings: %+v", settings)
+	}
+
+	saved, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if saved.Prompt != "> " || saved.HistoryView != 5 || !saved.DNSPrecheck || saved.Output != "json" {
+		t.Errorf("Expected settings to be written back, got %+v", saved)
+	}
+	if saved.Provider != "rdap" {
+		t.Errorf("Expected overrides not to be written back, got provider %q", saved.Provider)
+	}
+
+	if err := cmds.Set(context.Background(), "history_size many"); err == nil {
+		t.Error("Expected error for an invalid value")
+	}
+	if err := cmds.Get("prompt"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := cmds.Get("colour"); err == nil {
+		t.Error("Expected error for an unknown key")
+	}
+	if err := cmds.ShowConfig("show"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestDescribeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "rate limited",
+			err:      &api.Error{Kind: api.ErrRateLimited, StatusCode: 429},
+			expected: "rate limited by the API, wait a moment and try again",
+		},
+		{
+			name:     "server error",
+			err:      &api.Error{Kind: api.ErrServerError, StatusCode: 503},
+			expected: "the API is having trouble (HTTP 503), try again later",
+		},
+		{
+			name:     "invalid domain with payload",
+			err:      &api.Error{Kind: api.ErrInvalidDomain, StatusCode: 422, Message: "The domain format is invalid."},
+			expected: "invalid domain name: The domain format is invalid.",
+		},
+		{
+			name:     "malformed response",
+			err:      &api.Error{Kind: api.ErrMalformedResponse, StatusCode: 200, Err: errors.New("invalid character '<'")},
+			expected: "unexpected response from the API (HTTP 200)",
+		},
+		{
+			name:     "cancelled",
+			err:      fmt.Errorf("request error: %w", context.Canceled),
+			expected: "request cancelled",
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("network error"),
+			expected: "network error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if msg := describeError(tt.err); msg != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, msg)
+			}
+		})
+	}
+}
+
+type checkOnlyClient struct {
+	mockAPIClient
+}
+
+func (c *checkOnlyClient) Capabilities() api.Capability {
+	return api.CapabilityCheck
+}
+
+func TestCommands_MissingCapability(t *testing.T) {
+	called := false
+	client := &checkOnlyClient{mockAPIClient{
+		suggestDomainsFunc: func(string) (*domain.Response, error) {
+			called = true
+			return &domain.Response{}, nil
+		},
+	}}
+	cmds := NewCommands(client)
+
+	err := cmds.Suggest(context.Background(), "example")
+	if !errors.Is(err, api.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+	if called {
+		t.Error("Provider should not be called for an unsupported capability")
+	}
+	if cmds.LastOutcome() != OutcomeError {
+		t.Errorf("Expected error outcome, got %v", cmds.LastOutcome())
+	}
+}
+
+type fakeWhois struct {
+	record *whois.Record
+	err    error
+}
+
+func (f *fakeWhois) Lookup(ctx context.Context, domainName string) (*whois.Record, error) {
+	return f.record, f.err
+}
+
+func TestCommands_Whois(t *testing.T) {
+	expires := time.Date(2026, 8, 13, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		lookup          *fakeWhois
+		expectError     bool
+		expectedOutcome Outcome
+	}{
+		{
+			name: "registered domain",
+			lookup: &fakeWhois{record: &whois.Record{
+				Domain:      "example.com",
+				Registrar:   "Example Registrar, Inc.",
+				Expires:     &expires,
+				NameServers: []string{"a.iana-servers.net"},
+			}},
+			expectedOutcome: OutcomeTaken,
+		},
+		{
+			name:            "available domain",
+			lookup:          &fakeWhois{record: &whois.Record{Domain: "free-name.com", Available: true}},
+			expectedOutcome: OutcomeAvailable,
+		},
+		{
+			name:            "lookup error",
+			lookup:          &fakeWhois{err: whois.ErrNoServer},
+			expectError:     true,
+			expectedOutcome: OutcomeError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := NewCommands(&mockAPIClient{})
+			cmds.SetWhois(tt.lookup)
+
+			err := cmds.Run(context.Background(), "whois", "example.com")
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if cmds.LastOutcome() != tt.expectedOutcome {
+				t.Errorf("Expected outcome %v, got %v", tt.expectedOutcome, cmds.LastOutcome())
+			}
+		})
+	}
+}
+
+func TestCommands_Price(t *testing.T) {
+	mockClient := &mockAPIClient{
+		checkAvailabilityFunc: func(domainName string) (*domain.Response, error) {
+			item := domain.DomainData{Available: domainName == "free.com", Domain: domainName}
+			if item.Available {
+				item.Prices.Register.OneYear = 100000
+				item.Prices.Renew.OneYear = 150000
+			}
+			return &domain.Response{Data: []domain.DomainData{item}}, nil
+		},
+	}
+
+	tests := []struct {
+		name            string
+		args            string
+		expectedErr     error
+		expectedOutcome Outcome
+	}{
+		{name: "default period", args: "free.com", expectedOutcome: OutcomeAvailable},
+		{name: "explicit period", args: "free.com 3", expectedOutcome: OutcomeAvailable},
+		{name: "taken without prices", args: "taken.com", expectedOutcome: OutcomeTaken},
+		{name: "missing domain", args: "", expectedErr: ErrUsage, expectedOutcome: OutcomeError},
+		{name: "invalid period", args: "free.com 0", expectedErr: ErrUsage, expectedOutcome: OutcomeError},
+		{name: "period too long", args: "free.com 11", expectedErr: ErrUsage, expectedOutcome: OutcomeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmds := NewCommands(mockClient)
+
+			err := cmds.Run(context.Background(), "price", tt.args)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+			}
+			if cmds.LastOutcome() != tt.expectedOutcome {
+				t.Errorf("Expected outcome %v, got %v", tt.expectedOutcome, cmds.LastOutcome())
+			}
+		})
+	}
+
+	cmds := NewCommands(&checkOnlyClient{*mockClient})
+	if err := cmds.Price(context.Background(), "free.com", 1); !errors.Is(err, api.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported, got %v", err)
+	}
+}
+
+func TestPriceFields_Renew(t *testing.T) {
+	withRenew := domain.DomainData{Domain: "free.com", Available: true}
+	withRenew.Prices.Register.OneYear = 100000
+	withRenew.Prices.Renew.OneYear = 150000
+
+	withoutRenew := domain.DomainData{Domain: "free.com", Available: true}
+	withoutRenew.Prices.Register.OneYear = 100000
+
+	tests := []struct {
+		name     string
+		item     domain.DomainData
+		expected any
+	}{
+		{name: "renewal price from the API", item: withRenew, expected: output.Price(150000)},
+		{name: "no renewal price", item: withoutRenew, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got any
+			for _, f := range priceFields(tt.item, 3) {
+				if f.Key == "renew" {
+					got = f.Value
+				}
+			}
+			if got != tt.expected {
+				t.Errorf("Expected renew %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+type fakeDNS map[string]bool
+
+func (f fakeDNS) Registered(ctx context.Context, domainName string) (bool, error) {
+	return f[domainName], nil
+}
+
+func TestCommands_DNSPrecheck(t *testing.T) {
+	var apiCalls [][]string
+	mockClient := &mockAPIClient{
+		checkAvailabilityFunc: func(domainName string) (*domain.Response, error) {
+			apiCalls = append(apiCalls, []string{domainName})
+			return &domain.Response{Data: []domain.DomainData{{Available: true, Domain: domainName}}}, nil
+		},
+		checkAvailabilityBatchFunc: func(domainNames []string) (*domain.Response, error) {
+			apiCalls = append(apiCalls, domainNames)
+			result := &domain.Response{}
+			for _, name := range domainNames {
+				result.Data = append(result.Data, domain.DomainData{Available: true, Domain: name})
+			}
+			return result, nil
+		},
+	}
+
+	cmds := NewCommands(mockClient)
+	cmds.SetDNSChecker(fakeDNS{"taken.com": true})
+	cmds.EnableDNSPrecheck(true)
+
+	if err := cmds.Search(context.Background(), "taken.com"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(apiCalls) != 0 {
+		t.Errorf("Expected no API call for a name that resolves, got %v", apiCalls)
+	}
+	if cmds.LastOutcome() != OutcomeTaken {
+		t.Errorf("Expected taken outcome, got %v", cmds.LastOutcome())
+	}
+
+	if err := cmds.Check(context.Background(), []string{"taken.com", "free.com"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(apiCalls) != 1 || len(apiCalls[0]) != 1 || apiCalls[0][0] != "free.com" {
+		t.Errorf("Expected only free.com to reach the API, got %v", apiCalls)
+	}
+
+	cmds.EnableDNSPrecheck(false)
+	_ = cmds.Search(context.Background(), "taken.com")
+	if len(apiCalls) != 2 {
+		t.Errorf("Expected the API to be used when the pre-check is off, got %v", apiCalls)
+	}
+}
+
+func TestCommands_CacheDisabled(t *testing.T) {
+	cmds := NewCommands(&mockAPIClient{})
+
+	if err := cmds.Cache("stats"); err == nil {
+		t.Error("Expected error when the client has no cache")
+	}
+}
+
 func TestCommands_Help(t *testing.T) {
 	mockClient := &mockAPIClient{}
 	cmds := NewCommands(mockClient)
 
-	cmds.Help()
+	if err := cmds.Help(""); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := cmds.Help("set"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := cmds.Help("frobnicate"); !errors.Is(err, ErrUnknownCommand) {
+		t.Errorf("Expected ErrUnknownCommand, got %v", err)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	noop := func(ctx context.Context, args string) error { return nil }
+
+	r := NewRegistry()
+	r.Register(NewCommand("exit", "exit", "Exit", noop, WithAliases("quit")))
+	r.Register(NewCommand("help", "help", "Help", noop))
+
+	if cmd, ok := r.Lookup("QUIT"); !ok || cmd.Name() != "exit" {
+		t.Errorf("Expected quit to resolve to exit, got %v", cmd)
+	}
+
+	called := false
+	r.Register(NewCommand("exit", "exit", "Leave the shell", func(ctx context.Context, args string) error {
+		called = true
+		return nil
+	}))
+
+	if len(r.Commands()) != 2 || r.Commands()[0].Summary() != "Leave the shell" {
+		t.Errorf("Expected exit to be replaced in place, got %d commands", len(r.Commands()))
+	}
+	if _, ok := r.Lookup("quit"); ok {
+		t.Error("Expected the replaced command's aliases to be dropped")
+	}
+	cmd, _ := r.Lookup("exit")
+	_ = cmd.Run(context.Background(), "")
+	if !called {
+		t.Error("Expected the replacement to run")
+	}
+
+	expected := []string{"exit", "help"}
+	if names := r.Names(); fmt.Sprint(names) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, names)
+	}
+}
+
+func TestCommands_Register(t *testing.T) {
+	cmds := NewCommands(&mockAPIClient{})
+
+	var got string
+	cmds.Register(NewCommand("echo", "echo <text>", "Print text", func(ctx context.Context, args string) error {
+		got = args
+		return nil
+	}, WithCompleter(func(args []string) []string { return []string{"hello"} })))
+
+	command, args := cmds.ParseInput("echo hello world")
+	if command != "echo" {
+		t.Fatalf("Expected echo, got %q", command)
+	}
+	if err := cmds.Run(context.Background(), command, args); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != "hello world" {
+		t.Errorf("Expected the command to receive its arguments, got %q", got)
+	}
+
+	cmd, _ := cmds.Registry().Lookup("echo")
+	if completions := cmd.Complete(nil); len(completions) != 1 || completions[0] != "hello" {
+		t.Errorf("Unexpected completions: %v", completions)
+	}
+
+	if err := cmds.Run(context.Background(), "history", ""); !errors.Is(err, ErrInteractive) {
+		t.Errorf("Expected ErrInteractive, got %v", err)
+	}
+}
+
+func TestCommands_CompleteSetting(t *testing.T) {
+	cmds := NewCommands(&mockAPIClient{})
+	set, _ := cmds.Registry().Lookup("set")
+
+	if values := set.Complete([]string{"output"}); len(values) != len(output.Formats) {
+		t.Errorf("Expected output formats, got %v", values)
+	}
+	if values := set.Complete([]string{"dns"}); len(values) != 2 {
+		t.Errorf("Expected on/off, got %v", values)
+	}
+	if values := set.Complete([]string{"prompt", "x"}); values != nil {
+		t.Errorf("Expected no completions after the value, got %v", values)
+	}
+}
+
+func TestCommands_TLDs(t *testing.T) {
+	var batches [][]string
+	mockClient := &mockAPIClient{
+		checkAvailabilityBatchFunc: func(domainNames []string) (*domain.Response, error) {
+			batches = append(batches, domainNames)
+			result := &domain.Response{}
+			for _, name := range domainNames {
+				switch name {
+				case "acme.broken":
+					return nil, &api.Error{Kind: api.ErrInvalidDomain, StatusCode: http.StatusUnprocessableEntity}
+				case "acme.busy":
+					return nil, &api.Error{Kind: api.ErrRateLimited, StatusCode: http.StatusTooManyRequests}
+				}
+				item := domain.DomainData{Domain: name, Available: name != "acme.com"}
+				item.Prices.Register.OneYear = len(name) * 1000
+				result.Data = append(result.Data, item)
+			}
+			return result, nil
+		},
+	}
+
+	cmds := NewCommands(mockClient)
+	cmds.SetTLDFile(filepath.Join(t.TempDir(), "tlds.json"))
+
+	if err := cmds.Run(context.Background(), "tlds", "acme.io --set com,net,org"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(batches) != 1 || fmt.Sprint(batches[0]) != "[acme.com acme.net acme.org]" {
+		t.Errorf("Expected one batch for the label, got %v", batches)
+	}
+	if cmds.LastOutcome() != OutcomeAvailable {
+		t.Errorf("Expected available outcome, got %v", cmds.LastOutcome())
+	}
+
+	batches = nil
+	if err := cmds.Run(context.Background(), "tlds", "acme --set=com,broken"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(batches) != 3 {
+		t.Errorf("Expected a batch with an invalid name to be retried name by name, got %v", batches)
+	}
+	if cmds.LastOutcome() != OutcomeTaken {
+		t.Errorf("Expected taken outcome, got %v", cmds.LastOutcome())
+	}
+
+	batches = nil
+	if err := cmds.Run(context.Background(), "tlds", "acme --set=com,busy"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(batches) != 1 {
+		t.Errorf("Expected a rate-limited batch not to be split, got %v", batches)
+	}
+	if cmds.LastOutcome() != OutcomeError {
+		t.Errorf("Expected error outcome, got %v", cmds.LastOutcome())
+	}
+
+	for _, args := range []string{"", "--set popular", "acme --set", "acme --bogus"} {
+		if err := cmds.Run(context.Background(), "tlds", args); !errors.Is(err, ErrUsage) {
+			t.Errorf("tlds %q: expected ErrUsage, got %v", args, err)
+		}
+	}
+}
+
+func TestSortByAvailability(t *testing.T) {
+	items := []domain.DomainData{
+		{Domain: "b.com"},
+		{Domain: "c.com", Available: true},
+		{Domain: "a.com"},
+		{Domain: "d.com", Available: true},
+		{Domain: "e.com", Available: true},
+	}
+	items[1].Prices.Register.OneYear = 300
+	items[3].Prices.Register.OneYear = 100
+
+	sortByAvailability(items)
+
+	var order []string
+	for _, item := range items {
+		order = append(order, item.Domain)
+	}
+	if expected := "[d.com c.com e.com a.com b.com]"; fmt.Sprint(order) != expected {
+		t.Errorf("Expected %s, got %v", expected, order)
+	}
+}
+
+func TestReadDomains(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		column      string
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "one per line",
+			input:    "example.com\n\n  Example.NET \n# comment\nexample.com\n",
+			expected: []string{"example.com", "example.net"},
+		},
+		{
+			name:     "csv column",
+			input:    "name,Domain,owner\nAcme,acme.com,ops\nShop,\"shop.ir\",sales\nshort\n",
+			column:   "domain",
+			expected: []string{"acme.com", "shop.ir"},
+		},
+		{
+			name:        "missing column",
+			input:       "name,owner\nAcme,ops\n",
+			column:      "domain",
+			expectError: true,
+		},
+		{
+			name:   "empty csv",
+			column: "domain",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, err := ReadDomains(strings.NewReader(tt.input), tt.column)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fmt.Sprint(names) != fmt.Sprint(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestCommands_CheckFile(t *testing.T) {
+	var checked []string
+	var mu sync.Mutex
+	mockClient := &mockAPIClient{
+		checkAvailabilityBatchFunc: func(domainNames []string) (*domain.Response, error) {
+			mu.Lock()
+			checked = append(checked, domainNames...)
+			mu.Unlock()
+
+			result := &domain.Response{}
+			for _, name := range domainNames {
+				if strings.HasPrefix(name, "bad") {
+					return nil, errors.New("network error")
+				}
+				result.Data = append(result.Data, domain.DomainData{Domain: name, Available: !strings.HasPrefix(name, "taken")})
+			}
+			return result, nil
+		},
+	}
+
+	var lines []string
+	for i := 0; i < 45; i++ {
+		lines = append(lines, fmt.Sprintf("name%d.com", i))
+	}
+	path := filepath.Join(t.TempDir(), "names.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var progress strings.Builder
+	cmds := NewCommands(mockClient)
+	cmds.SetProgress(&progress)
+
+	if err := cmds.Run(context.Background(), "check-file", path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(checked) != 45 {
+		t.Errorf("Expected every name to be checked once, got %d", len(checked))
+	}
+	if cmds.LastOutcome() != OutcomeAvailable {
+		t.Errorf("Expected available outcome, got %v", cmds.LastOutcome())
+	}
+	if !strings.Contains(progress.String(), "Checked 45/45") {
+		t.Errorf("Expected progress output, got %q", progress.String())
+	}
+
+	cmds.SetInput(strings.NewReader("domain\nfree.com\ntaken.com\nbad.com\n"))
+	if err := cmds.Run(context.Background(), "check", "- --column domain"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cmds.LastOutcome() != OutcomeError {
+		t.Errorf("Expected error outcome when a lookup fails, got %v", cmds.LastOutcome())
+	}
+
+	if err := cmds.Run(context.Background(), "check-file", filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+	if err := cmds.Run(context.Background(), "check-file", ""); !errors.Is(err, ErrUsage) {
+		t.Errorf("Expected ErrUsage, got %v", err)
+	}
+}
+
+func TestCommands_Export(t *testing.T) {
+	mockClient := &mockAPIClient{
+		checkAvailabilityFunc: func(domainName string) (*domain.Response, error) {
+			return &domain.Response{Data: []domain.DomainData{{Available: true, Domain: domainName}}}, nil
+		},
+	}
+	cmds := NewCommands(mockClient)
+	cmds.SetProvider("limoo")
+	dir := t.TempDir()
+
+	if err := cmds.Run(context.Background(), "export", "csv "+filepath.Join(dir, "empty.csv")); err == nil {
+		t.Error("Expected error when there is nothing to export")
+	}
+
+	_ = cmds.Search(context.Background(), "example.com")
+	_ = cmds.Search(context.Background(), "example.net")
+
+	saved := filepath.Join(dir, "results.json")
+	if err := cmds.Run(context.Background(), "export", "json "+saved); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "example.net") || !strings.Contains(string(data), `"provider": "limoo"`) {
+		t.Errorf("Unexpected export: %s", data)
+	}
+
+	fresh := NewCommands(mockClient)
+	markdown := filepath.Join(dir, "results.md")
+	if err := fresh.Run(context.Background(), "export", "md "+markdown+" --from "+saved); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(markdown); strings.Count(string(data), "\n") != 4 {
+		t.Errorf("Expected a markdown table with two rows, got %q", data)
+	}
+
+	if err := cmds.Run(context.Background(), "export", "xlsx out.xlsx"); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+	if err := cmds.Run(context.Background(), "export", "csv"); !errors.Is(err, ErrUsage) {
+		t.Errorf("Expected ErrUsage, got %v", err)
+	}
+}
+
+func TestCommands_Results(t *testing.T) {
+	calls := 0
+	mockClient := &mockAPIClient{
+		checkAvailabilityFunc: func(domainName string) (*domain.Response, error) {
+			calls++
+			return &domain.Response{Data: []domain.DomainData{{Available: domainName == "free.com", Domain: domainName}}}, nil
+		},
+	}
+
+	store := results.NewStore(filepath.Join(t.TempDir(), "results.jsonl"))
+	cmds := NewCommands(mockClient)
+	cmds.SetStore(store)
+
+	_ = cmds.Search(context.Background(), "free.com")
+	_ = cmds.Search(context.Background(), "taken.com")
+
+	saved, err := store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(saved) != 2 || saved[0].Domain != "free.com" || saved[0].CheckedAt.IsZero() {
+		t.Errorf("Expected lookups to be saved, got %+v", saved)
+	}
+
+	calls = 0
+	for _, args := range []string{"", "--available", "free --since 1h", "--all --limit=1", "--provider limoo"} {
+		if err := cmds.Run(context.Background(), "results", args); err != nil {
+			t.Errorf("results %s: unexpected error: %v", args, err)
+		}
+	}
+	if err := cmds.Run(context.Background(), "results", "show taken.com"); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if cmds.LastOutcome() != OutcomeTaken {
+		t.Errorf("Expected the saved answer's outcome, got %v", cmds.LastOutcome())
+	}
+	if calls != 0 {
+		t.Errorf("Expected results to work offline, got %d API calls", calls)
+	}
+
+	if err := cmds.Run(context.Background(), "results", "show missing.com"); err == nil {
+		t.Error("Expected error for a domain without saved results")
+	}
+	if err := cmds.Run(context.Background(), "results", "--since never"); err == nil {
+		t.Error("Expected error for an invalid --since")
+	}
+	if err := cmds.Run(context.Background(), "results", "--bogus"); !errors.Is(err, ErrUsage) {
+		t.Errorf("Expected ErrUsage, got %v", err)
+	}
+
+	exported := filepath.Join(t.TempDir(), "saved.csv")
+	if err := NewCommands(mockClient).Run(context.Background(), "export", "csv "+exported); err == nil {
+		t.Error("Expected error when the session has no results")
+	}
+	fresh := NewCommands(mockClient)
+	fresh.SetStore(store)
+	if err := fresh.Run(context.Background(), "export", "csv "+exported+" --saved"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(exported); strings.Count(string(data), "\n") != 3 {
+		t.Errorf("Expected the saved results to be exported, got %q", data)
+	}
+
+	if err := cmds.Run(context.Background(), "results", "clear"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if saved, _ := store.Load(); len(saved) != 0 {
+		t.Errorf("Expected saved results to be cleared, got %d", len(saved))
+	}
+}
+
+type privateClient struct {
+	mockAPIClient
+	private bool
+}
+
+func (p *privateClient) SetPrivate(private bool) {
+	p.private = private
+}
+
+func TestCommands_Private(t *testing.T) {
+	client := &privateClient{mockAPIClient: mockAPIClient{
+		checkAvailabilityFunc: func(domainName string) (*domain.Response, error) {
+			return &domain.Response{Data: []domain.DomainData{{Available: true, Domain: domainName}}}, nil
+		},
+	}}
+
+	store := results.NewStore(filepath.Join(t.TempDir(), "results.jsonl"))
+	cmds := NewCommands(client)
+	cmds.SetStore(store)
+
+	_ = cmds.Search(context.Background(), "public.com")
+	cmds.SetPrivate(true)
+	if !cmds.Private() || !client.private {
+		t.Fatal("Expected private mode to reach the API client")
+	}
+	_ = cmds.Search(context.Background(), "secret.com")
+
+	if saved, _ := store.Load(); len(saved) != 1 || saved[0].Domain != "public.com" {
+		t.Errorf("Expected private lookups to stay out of the store, got %+v", saved)
+	}
+	if err := cmds.ShowResult("secret.com"); err != nil {
+		t.Errorf("Expected private lookups to be listed during the session: %v", err)
+	}
+	if len(cmds.Session().All()) != 2 {
+		t.Errorf("Expected both lookups in the session, got %d", len(cmds.Session().All()))
+	}
+
+	cmds.SetPrivate(false)
+	if client.private {
+		t.Error("Expected private mode to be turned off in the API client")
+	}
+	if err := cmds.ShowResult("secret.com"); err == nil {
+		t.Error("Expected private lookups to be forgotten once private mode ends")
+	}
+	if items := cmds.Session().All(); len(items) != 1 || items[0].Domain != "public.com" {
+		t.Errorf("Expected private lookups to be dropped from the session export, got %+v", items)
+	}
+	if err := cmds.Run(context.Background(), "incognito", "on"); !errors.Is(err, ErrInteractive) {
+		t.Errorf("Expected ErrInteractive, got %v", err)
+	}
 }
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"domainshell/internal/results"
+	"domainshell/pkg/domain"
+)
+
+const exportUsage = "export csv|json|markdown <path> [--saved | --from saved.json]"
+
+func (c *Commands) SetProvider(name string) {
+	c.provider = name
+}
+
+func (c *Commands) Session() *results.Session {
+	if c.session == nil {
+		c.session = results.NewSession()
+	}
+	return c.session
+}
+
+func (c *Commands) SetStore(store *results.Store) {
+	c.store = store
+}
+
+type privateSetter interface {
+	SetPrivate(private bool)
+}
+
+func (c *Commands) SetPrivate(private bool) {
+	c.private = private
+	if !private {
+		c.unsaved = nil
+	}
+	c.Session().SetPrivate(private)
+	if ps, ok := c.apiClient.(privateSetter); ok {
+		ps.SetPrivate(private)
+	}
+}
+
+func (c *Commands) Private() bool {
+	return c.private
+}
+
+func (c *Commands) record(items ...domain.DomainData) {
+	now := time.Now()
+	recorded := make([]results.Result, len(items))
+	for i, item := range items {
+		recorded[i] = results.New(item, c.provider, now)
+	}
+	c.Session().Add(recorded...)
+
+	if c.private {
+		c.unsaved = append(c.unsaved, recorded...)
+		return
+	}
+	if c.store != nil {
+		if err := c.store.Append(recorded...); err != nil && !c.storeFailed {
+			c.storeFailed = true
+			fmt.Fprintf(os.Stderr, "Warning: failed to save results: %v\n", err)
+		}
+	}
+}
+
+func (c *Commands) runExport(ctx context.Context, args string) error {
+	var format, path, from string
+	saved := false
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "--saved":
+			saved = true
+		case field == "--from" && i+1 < len(fields):
+			from = fields[i+1]
+			i++
+		case strings.HasPrefix(field, "--from="):
+			from = strings.TrimPrefix(field, "--from=")
+		case format == "":
+			format = field
+		case path == "":
+			path = field
+		default:
+			return c.usage(exportUsage)
+		}
+	}
+	if path == "" {
+		return c.usage(exportUsage)
+	}
+
+	f, err := results.ParseFormat(format)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	items := c.Session().All()
+	switch {
+	case saved && from != "":
+		return c.usage(exportUsage)
+	case saved:
+		items, err = c.savedResults()
+	case from != "":
+		items, err = results.ReadFile(from)
+	}
+	if err != nil {
+		return c.fail(err)
+	}
+	return c.Export(f, path, items)
+}
+
+func (c *Commands) Export(format results.Format, path string, items []results.Result) error {
+	if len(items) == 0 {
+		return c.fail(fmt.Errorf("no results to export"))
+	}
+
+	if path == "-" {
+		if err := results.Write(os.Stdout, format, items); err != nil {
+			return c.fail(err)
+		}
+		return nil
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return c.fail(err)
+	}
+	if err := results.Write(f, format, items); err != nil {
+		f.Close()
+		return c.fail(err)
+	}
+	if err := f.Close(); err != nil {
+		return c.fail(err)
+	}
+	c.out().Message(fmt.Sprintf("Exported %d results to %s", len(items), path))
+	return nil
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+type RunFunc func(ctx context.Context, args string) error
+
+type CompleteFunc func(args []string) []string
+
+type Command interface {
+	Name() string
+	Aliases() []string
+	Usage() string
+	Summary() string
+	Complete(args []string) []string
+	Run(ctx context.Context, args string) error
+}
+
+type CommandOption func(*command)
+
+func WithAliases(aliases ...string) CommandOption {
+	return func(c *command) {
+		c.aliases = append(c.aliases, aliases...)
+	}
+}
+
+func WithCompleter(complete CompleteFunc) CommandOption {
+	return func(c *command) {
+		c.complete = complete
+	}
+}
+
+type command struct {
+	name     string
+	aliases  []string
+	usage    string
+	summary  string
+	complete CompleteFunc
+	run      RunFunc
+}
+
+func NewCommand(name, usage, summary string, run RunFunc, opts ...CommandOption) Command {
+	c := &command{name: name, usage: usage, summary: summary, run: run}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *command) Name() string      { return c.name }
+func (c *command) Aliases() []string { return c.aliases }
+func (c *command) Usage() string     { return c.usage }
+func (c *command) Summary() string   { return c.summary }
+
+func (c *command) Complete(args []string) []string {
+	if c.complete == nil {
+		return nil
+	}
+	return c.complete(args)
+}
+
+func (c *command) Run(ctx context.Context, args string) error {
+	return c.run(ctx, args)
+}
+
+type Registry struct {
+	commands []Command
+	lookup   map[string]Command
+}
+
+func NewRegistry() *Registry {
+	return &Registry{lookup: make(map[string]Command)}
+}
+
+func (r *Registry) Register(cmd Command) {
+	for i, existing := range r.commands {
+		if existing.Name() == cmd.Name() {
+			r.commands[i] = cmd
+			r.index()
+			return
+		}
+	}
+	r.commands = append(r.commands, cmd)
+	r.index()
+}
+
+func (r *Registry) index() {
+	r.lookup = make(map[string]Command, len(r.commands))
+	for _, cmd := range r.commands {
+		r.lookup[cmd.Name()] = cmd
+		for _, alias := range cmd.Aliases() {
+			r.lookup[alias] = cmd
+		}
+	}
+}
+
+func (r *Registry) Lookup(name string) (Command, bool) {
+	cmd, ok := r.lookup[strings.ToLower(name)]
+	return cmd, ok
+}
+
+func (r *Registry) Commands() []Command {
+	return r.commands
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.lookup))
+	for name := range r.lookup {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
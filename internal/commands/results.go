@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"domainshell/internal/output"
+	"domainshell/internal/results"
+	"domainshell/internal/since"
+)
+
+const (
+	resultsUsage = "results [text] [--available|--taken] [--since 24h] [--provider p] [--all] [--limit n] | results show <domain> | results clear"
+
+	defaultResultsLimit = 50
+)
+
+var resultFlags = []string{"--available", "--taken", "--since", "--provider", "--all", "--limit"}
+
+func (c *Commands) runResults(ctx context.Context, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) > 0 {
+		switch fields[0] {
+		case "show":
+			if len(fields) != 2 {
+				return c.usage("results show <domain>")
+			}
+			return c.ShowResult(fields[1])
+		case "clear":
+			return c.ClearResults()
+		}
+	}
+
+	filter := results.Filter{Limit: defaultResultsLimit}
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		value := ""
+		if name, v, ok := strings.Cut(field, "="); ok && strings.HasPrefix(name, "--") {
+			field, value = name, v
+		} else if (field == "--since" || field == "--provider" || field == "--limit") && i+1 < len(fields) {
+			value = fields[i+1]
+			i++
+		}
+
+		switch field {
+		case "--available", "--taken":
+			available := field == "--available"
+			filter.Available = &available
+		case "--all":
+			filter.All = true
+		case "--since":
+			from, err := since.Parse(value, time.Now())
+			if err != nil {
+				return c.fail(err)
+			}
+			filter.Since = from
+		case "--provider":
+			filter.Provider = value
+		case "--limit":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return c.usage(resultsUsage)
+			}
+			filter.Limit = n
+		default:
+			if strings.HasPrefix(field, "-") || filter.Domain != "" {
+				return c.usage(resultsUsage)
+			}
+			filter.Domain = field
+		}
+	}
+
+	items, err := c.savedResults()
+	if err != nil {
+		return c.fail(err)
+	}
+
+	c.out().Results(filter.Apply(items))
+	return nil
+}
+
+func (c *Commands) savedResults() ([]results.Result, error) {
+	if c.store == nil {
+		return c.Session().All(), nil
+	}
+
+	items, err := c.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if n := c.store.Skipped(); n > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %d malformed line(s) in %s\n", n, c.store.Path())
+	}
+	return append(items, c.unsaved...), nil
+}
+
+func (c *Commands) ShowResult(domainName string) error {
+	items, err := c.savedResults()
+	if err != nil {
+		return c.fail(err)
+	}
+
+	var found *results.Result
+	for _, item := range results.Latest(items) {
+		if strings.EqualFold(item.Domain, domainName) {
+			found = &item
+			break
+		}
+	}
+	if found == nil {
+		return c.fail(fmt.Errorf("no saved result for %s", domainName))
+	}
+
+	c.outcome = outcomeOf(found.DomainData)
+	fields := []output.Field{
+		{Key: "domain", Value: found.Domain},
+		{Key: "available", Value: found.Available},
+		{Key: "checked_at", Value: found.CheckedAt.Local().Format("2006-01-02 15:04:05")},
+		{Key: "provider", Value: found.Provider},
+	}
+	prices := found.Prices
+	for _, price := range []struct {
+		key    string
+		amount int
+	}{
+		{"register", prices.Register.OneYear},
+		{"renew", prices.Renew.OneYear},
+		{"transfer", prices.Transfer.OneYear},
+		{"restore", prices.Restore.OneYear},
+	} {
+		if price.amount > 0 {
+			fields = append(fields, output.Field{Key: price.key, Value: output.Price(price.amount)})
+		}
+	}
+	if found.OnSale {
+		fields = append(fields, output.Field{Key: "on_sale", Value: true})
+	}
+	if found.Premium {
+		fields = append(fields, output.Field{Key: "premium", Value: true})
+	}
+	if found.Reason != "" {
+		fields = append(fields, output.Field{Key: "reason", Value: found.Reason})
+	}
+	if found.Registrar != "" {
+		fields = append(fields, output.Field{Key: "registrar", Value: found.Registrar})
+	}
+	if found.Expires != nil {
+		fields = append(fields, output.Field{Key: "expires", Value: *found.Expires})
+	}
+	if len(found.Status) > 0 {
+		fields = append(fields, output.Field{Key: "status", Value: found.Status})
+	}
+
+	c.out().Fields("Saved result for "+found.Domain, fields)
+	return nil
+}
+
+func (c *Commands) ClearResults() error {
+	if c.store == nil {
+		return c.fail(errors.New("no result store configured"))
+	}
+	if err := c.store.Clear(); err != nil {
+		return c.fail(err)
+	}
+	c.unsaved = nil
+	c.out().Message("Saved results cleared")
+	return nil
+}
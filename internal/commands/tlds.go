@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"domainshell/internal/api"
+	"domainshell/internal/tlds"
+)
+
+const tldsUsage = "tlds <label> [--set popular|iran|all|com,net,...]"
+
+func (c *Commands) SetTLDFile(path string) {
+	c.tldFile = path
+}
+
+func (c *Commands) TLDSets() (tlds.Sets, error) {
+	return tlds.Load(c.tldFile)
+}
+
+func (c *Commands) runTLDs(ctx context.Context, args string) error {
+	var label, set string
+	fields := strings.Fields(args)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "--set" && i+1 < len(fields):
+			set = fields[i+1]
+			i++
+		case strings.HasPrefix(field, "--set="):
+			set = strings.TrimPrefix(field, "--set=")
+		case label == "" && !strings.HasPrefix(field, "-"):
+			label = field
+		default:
+			return c.usage(tldsUsage)
+		}
+	}
+	if label == "" {
+		return c.usage(tldsUsage)
+	}
+
+	return c.TLDs(ctx, label, set)
+}
+
+func (c *Commands) TLDs(ctx context.Context, label, set string) error {
+	if !api.Supports(c.apiClient, api.CapabilityCheck) {
+		return c.fail(fmt.Errorf("availability checks are %w", api.ErrUnsupported))
+	}
+
+	sets, err := c.TLDSets()
+	if err != nil {
+		return c.fail(err)
+	}
+	list, err := sets.Resolve(set)
+	if err != nil {
+		return c.fail(err)
+	}
+
+	label, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(label)), ".")
+	names := make([]string, len(list))
+	for i, tld := range list {
+		names[i] = label + "." + tld
+	}
+
+	results := c.lookupAll(ctx, names, bulkWorkers, nil)
+	if err := ctx.Err(); err != nil {
+		return c.fail(err)
+	}
+
+	items, failures, summary := summarize(results)
+	sortByAvailability(items)
+
+	c.outcome = OutcomeError
+	for _, item := range items {
+		if item.Available {
+			c.outcome = OutcomeAvailable
+			break
+		}
+		c.outcome = OutcomeTaken
+	}
+
+	c.record(items...)
+	c.out().Batch(items, failures, summary)
+
+	return nil
+}
+
+func (c *Commands) completeTLDs(args []string) []string {
+	if len(args) == 0 || args[len(args)-1] != "--set" {
+		if len(args) == 1 {
+			return []string{"--set"}
+		}
+		return nil
+	}
+
+	sets, err := c.TLDSets()
+	if err != nil {
+		return nil
+	}
+	return sets.Names()
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	legacyFileName = "config.json"
+	EnvPrefix      = "DOMAINSHELL_"
+)
+
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+type Config struct {
+	Provider             string   `json:"provider"`
+	RateLimit            float64  `json:"rate_limit"`
+	RateBurst            int      `json:"rate_burst"`
+	Cache                bool     `json:"cache"`
+	CacheTTLAvailability Duration `json:"cache_ttl_availability"`
+	CacheTTLSuggestions  Duration `json:"cache_ttl_suggestions"`
+	DNSPrecheck          bool     `json:"dns_precheck"`
+	Resolver             string   `json:"resolver"`
+	Output               string   `json:"output"`
+	Prompt               string   `json:"prompt"`
+	BaseURL              string   `json:"base_url,omitempty"`
+	HistorySize          int      `json:"history_size"`
+	HistoryView          int      `json:"history_view"`
+	Currency             string   `json:"currency,omitempty"`
+	Locale               string   `json:"locale,omitempty"`
+	RatesFile            string   `json:"rates_file,omitempty"`
+	RatesURL             string   `json:"rates_url,omitempty"`
+}
+
+func Default() Config {
+	return Config{
+		Provider:             "limoo",
+		RateLimit:            2,
+		RateBurst:            5,
+		Cache:                true,
+		CacheTTLAvailability: Duration(time.Hour),
+		CacheTTLSuggestions:  Duration(12 * time.Hour),
+		DNSPrecheck:          false,
+		Resolver:             "1.1.1.1:53",
+		Output:               "table",
+		Prompt:               "domain → ",
+		HistorySize:          1000,
+		HistoryView:          20,
+	}
+}
+
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = os.ReadFile(filepath.Join(filepath.Dir(path), legacyFileName))
+	}
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Default(), fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+func (c Config) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func Keys() []string {
+	t := reflect.TypeOf(Config{})
+	keys := make([]string, 0, t.NumField())
+	for i := range t.NumField() {
+		keys = append(keys, keyOf(t.Field(i)))
+	}
+	return keys
+}
+
+func (c Config) Get(key string) (string, error) {
+	v, err := c.field(key)
+	if err != nil {
+		return "", err
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		return fmt.Sprint(v.Interface()), nil
+	}
+}
+
+func (c *Config) Set(key, value string) error {
+	v, err := c.field(key)
+	if err != nil {
+		return err
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := parseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value for %s: expected a non-negative number, got %q", key, value)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("invalid value for %s: expected a non-negative number, got %q", key, value)
+		}
+		v.SetFloat(f)
+	}
+	return nil
+}
+
+func (c *Config) ApplyEnv(lookup func(string) (string, bool)) error {
+	for _, key := range Keys() {
+		value, ok := lookup(EnvPrefix + strings.ToUpper(key))
+		if !ok {
+			continue
+		}
+		if err := c.Set(key, value); err != nil {
+			return fmt.Errorf("%s%s: %w", EnvPrefix, strings.ToUpper(key), err)
+		}
+	}
+	return nil
+}
+
+func (c *Config) field(key string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	for i := range v.NumField() {
+		if keyOf(v.Type().Field(i)) == strings.ToLower(key) {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("unknown config key %q", key)
+}
+
+func keyOf(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
+
+func parseBool(value string) (bool, error) {
+	switch strings.ToLower(value) {
+	case "on", "true", "yes", "1":
+		return true, nil
+	case "off", "false", "no", "0":
+		return false, nil
+	}
+	return false, fmt.Errorf("expected on or off, got %q", value)
+}
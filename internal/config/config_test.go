@@ -0,0 +1,186 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		expected    Config
+		expectError bool
+	}{
+		{
+			name:     "missing file uses defaults",
+			expected: Default(),
+		},
+		{
+			name:    "overrides defaults",
+			content: `{"provider": "mock", "rate_limit": 0.5, "rate_burst": 1, "cache": false, "cache_ttl_availability": "10m", "cache_ttl_suggestions": "2h", "dns_precheck": true, "resolver": "127.0.0.1:5353"}`,
+			expected: Config{
+				Provider:             "mock",
+				RateLimit:            0.5,
+				RateBurst:            1,
+				Cache:                false,
+				CacheTTLAvailability: Duration(10 * time.Minute),
+				CacheTTLSuggestions:  Duration(2 * time.Hour),
+				DNSPrecheck:          true,
+				Resolver:             "127.0.0.1:5353",
+				Output:               "table",
+				Prompt:               "domain → ",
+				HistorySize:          1000,
+				HistoryView:          20,
+			},
+		},
+		{
+			name:    "partial file keeps other defaults",
+			content: `{"rate_limit": 10}`,
+			expected: func() Config {
+				cfg := Default()
+				cfg.RateLimit = 10
+				return cfg
+			}(),
+		},
+		{
+			name:        "invalid duration",
+			content:     `{"cache_ttl_availability": "forever"}`,
+			expected:    Default(),
+			expectError: true,
+		},
+		{
+			name:        "invalid file",
+			content:     `rate_limit = 10`,
+			expected:    Default(),
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			cfg, err := Load(path)
+			if tt.expectError && err == nil {
+				t.Error("Expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if cfg != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, cfg)
+			}
+		})
+	}
+}
+
+func TestLoad_LegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, legacyFileName), []byte(`{"provider": "rdap"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Provider != "rdap" {
+		t.Errorf("Expected settings from %s, got %+v", legacyFileName, cfg)
+	}
+}
+
+func TestConfig_SetGet(t *testing.T) {
+	tests := []struct {
+		key         string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{key: "prompt", value: "> ", expected: "> "},
+		{key: "history_size", value: "50", expected: "50"},
+		{key: "rate_limit", value: "0.5", expected: "0.5"},
+		{key: "cache", value: "off", expected: "false"},
+		{key: "cache_ttl_suggestions", value: "90m", expected: "1h30m0s"},
+		{key: "HISTORY_VIEW", value: "5", expected: "5"},
+		{key: "history_size", value: "-1", expectError: true},
+		{key: "cache", value: "maybe", expectError: true},
+		{key: "cache_ttl_availability", value: "soon", expectError: true},
+		{key: "colour", value: "blue", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			cfg := Default()
+			err := cfg.Set(tt.key, tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got, err := cfg.Get(tt.key)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestConfig_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config")
+
+	cfg := Default()
+	cfg.Prompt = "whois> "
+	cfg.HistorySize = 10
+	cfg.CacheTTLAvailability = Duration(5 * time.Minute)
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded != cfg {
+		t.Errorf("Expected %+v, got %+v", cfg, loaded)
+	}
+}
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"DOMAINSHELL_PROMPT":       "$ ",
+		"DOMAINSHELL_HISTORY_VIEW": "50",
+	}
+	lookup := func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+
+	cfg := Default()
+	if err := cfg.ApplyEnv(lookup); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Prompt != "$ " || cfg.HistoryView != 50 {
+		t.Errorf("Expected environment overrides, got %+v", cfg)
+	}
+
+	env["DOMAINSHELL_RATE_BURST"] = "lots"
+	if err := cfg.ApplyEnv(lookup); err == nil {
+		t.Error("Expected error for an invalid environment value")
+	}
+}
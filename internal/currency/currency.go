@@ -0,0 +1,77 @@
+package currency
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Code string
+
+const (
+	IRT Code = "IRT"
+	IRR Code = "IRR"
+	USD Code = "USD"
+	EUR Code = "EUR"
+)
+
+var Codes = []Code{IRT, IRR, USD, EUR}
+
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocalePersian Locale = "fa"
+)
+
+type unit struct {
+	name     string
+	faName   string
+	symbol   string
+	decimals int
+}
+
+var units = map[Code]unit{
+	IRT: {name: "Toman", faName: "تومان"},
+	IRR: {name: "IRR", faName: "ریال"},
+	USD: {name: "USD", faName: "دلار", symbol: "$", decimals: 2},
+	EUR: {name: "EUR", faName: "یورو", symbol: "€", decimals: 2},
+}
+
+var aliases = map[string]Code{
+	"toman": IRT,
+	"rial":  IRR,
+}
+
+func ParseCode(s string) (Code, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if code, ok := aliases[s]; ok {
+		return code, nil
+	}
+	code := Code(strings.ToUpper(s))
+	if _, ok := units[code]; !ok {
+		return "", fmt.Errorf("unknown currency %q (want %s)", s, codeList())
+	}
+	return code, nil
+}
+
+func ParseLocale(s string) (Locale, error) {
+	switch Locale(strings.ToLower(strings.TrimSpace(s))) {
+	case LocaleEnglish:
+		return LocaleEnglish, nil
+	case LocalePersian:
+		return LocalePersian, nil
+	}
+	return "", fmt.Errorf("unknown locale %q (want en|fa)", s)
+}
+
+func codeList() string {
+	names := make([]string, len(Codes))
+	for i, c := range Codes {
+		names[i] = string(c)
+	}
+	return strings.Join(names, "|")
+}
+
+func (c Code) Fixed() bool {
+	return c == IRT || c == IRR
+}
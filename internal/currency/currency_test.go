@@ -0,0 +1,177 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestParseCode(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    Code
+		expectError bool
+	}{
+		{input: "usd", expected: USD},
+		{input: "EUR", expected: EUR},
+		{input: "toman", expected: IRT},
+		{input: "IRT", expected: IRT},
+		{input: "Rial", expected: IRR},
+		{input: "gbp", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			code, err := ParseCode(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if code != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, code)
+			}
+		})
+	}
+}
+
+func TestFormatter_FormatPrice(t *testing.T) {
+	rates := Rates{USD: 100000, EUR: 125000}
+
+	tests := []struct {
+		name     string
+		code     Code
+		locale   Locale
+		amount   int
+		expected string
+	}{
+		{name: "toman", code: IRT, locale: LocaleEnglish, amount: 1450000, expected: "1,450,000 Toman"},
+		{name: "rial", code: IRR, locale: LocaleEnglish, amount: 1450000, expected: "14,500,000 IRR"},
+		{name: "dollar", code: USD, locale: LocaleEnglish, amount: 1450000, expected: "$14.50"},
+		{name: "euro rounding", code: EUR, locale: LocaleEnglish, amount: 100000, expected: "€0.80"},
+		{name: "large dollar amount", code: USD, locale: LocaleEnglish, amount: 123456700000, expected: "$1,234,567.00"},
+		{name: "small toman amount", code: IRT, locale: LocaleEnglish, amount: 900, expected: "900 Toman"},
+		{name: "persian toman", code: IRT, locale: LocalePersian, amount: 1450000, expected: "۱٬۴۵۰٬۰۰۰ تومان"},
+		{name: "persian dollar", code: USD, locale: LocalePersian, amount: 1450000, expected: "۱۴٫۵۰ دلار"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFormatter(tt.code, tt.locale, rates)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got := f.FormatPrice(tt.amount); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+
+	if _, err := NewFormatter(USD, LocaleEnglish, nil); !errors.Is(err, ErrNoRate) {
+		t.Errorf("Expected ErrNoRate without rates, got %v", err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    Rates
+		expectError bool
+	}{
+		{name: "known", input: `{"rates": {"USD": 100000, "eur": 125000}}`, expected: Rates{USD: 100000, EUR: 125000}},
+		{name: "unsupported skipped", input: `{"rates": {"USD": 100000, "GBP": 140000}}`, expected: Rates{USD: 100000}},
+		{name: "invalid", input: `{"rates": [}`, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates, err := Parse([]byte(tt.input))
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(rates) != len(tt.expected) {
+				t.Fatalf("Expected %v, got %v", tt.expected, rates)
+			}
+			for code, rate := range tt.expected {
+				if rates[code] != rate {
+					t.Errorf("Expected %s = %v, got %v", code, rate, rates[code])
+				}
+			}
+		})
+	}
+}
+
+func TestSource_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	if err := os.WriteFile(path, []byte(`{"rates": {"usd": 100000, "EUR": 125000}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	rates, err := (&Source{Path: path}).Rates(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rates[USD] != 100000 || rates[EUR] != 125000 {
+		t.Errorf("Unexpected rates: %v", rates)
+	}
+
+	if _, err := (&Source{Path: filepath.Join(t.TempDir(), "missing.json")}).Rates(context.Background()); err == nil {
+		t.Error("Expected error for a missing rates file")
+	}
+}
+
+func TestSource_URL(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"rates": {"USD": 100000}}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "rates.json")
+	source := &Source{Path: path, URL: server.URL, MaxAge: time.Hour, HTTPClient: server.Client()}
+
+	rates, err := source.Rates(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rates[USD] != 100000 {
+		t.Errorf("Unexpected rates: %v", rates)
+	}
+
+	if _, err := source.Rates(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("Expected the saved rates to be reused, got %d requests", requests)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	server.Close()
+	rates, err = source.Rates(context.Background())
+	if err != nil {
+		t.Fatalf("Expected stale rates when the endpoint is down, got %v", err)
+	}
+	if rates[USD] != 100000 {
+		t.Errorf("Unexpected rates: %v", rates)
+	}
+}
@@ -0,0 +1,93 @@
+package currency
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Formatter struct {
+	code   Code
+	locale Locale
+	rate   float64
+}
+
+func NewFormatter(code Code, locale Locale, rates Rates) (*Formatter, error) {
+	rate, err := rates.Rate(code)
+	if err != nil {
+		return nil, err
+	}
+	if locale == "" {
+		locale = LocaleEnglish
+	}
+	return &Formatter{code: code, locale: locale, rate: rate}, nil
+}
+
+func (f *Formatter) Code() Code {
+	return f.code
+}
+
+func (f *Formatter) Locale() Locale {
+	return f.locale
+}
+
+func (f *Formatter) Convert(toman int) float64 {
+	return float64(toman) / f.rate
+}
+
+func (f *Formatter) FormatPrice(toman int) string {
+	u := units[f.code]
+	number := f.number(f.Convert(toman), u.decimals)
+
+	if f.locale == LocalePersian {
+		return number + " " + u.faName
+	}
+	if u.symbol != "" {
+		return u.symbol + number
+	}
+	return number + " " + u.name
+}
+
+func (f *Formatter) number(amount float64, decimals int) string {
+	scale := math.Pow10(decimals)
+	amount = math.Round(amount*scale) / scale
+	s := strconv.FormatFloat(amount, 'f', decimals, 64)
+
+	whole, frac, _ := strings.Cut(s, ".")
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+
+	groupSep, decimalSep := ",", "."
+	if f.locale == LocalePersian {
+		groupSep, decimalSep = "٬", "٫"
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(groupSep)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(decimalSep)
+		b.WriteString(frac)
+	}
+
+	if f.locale == LocalePersian {
+		return persianDigits(b.String())
+	}
+	return b.String()
+}
+
+func persianDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return '۰' + (r - '0')
+		}
+		return r
+	}, s)
+}
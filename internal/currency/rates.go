@@ -0,0 +1,162 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const DefaultMaxAge = 24 * time.Hour
+
+var ErrNoRate = errors.New("no exchange rate")
+
+type Rates map[Code]float64
+
+type ratesDocument struct {
+	Updated time.Time          `json:"updated,omitzero"`
+	Rates   map[string]float64 `json:"rates"`
+}
+
+func (r Rates) Rate(code Code) (float64, error) {
+	switch code {
+	case IRT:
+		return 1, nil
+	case IRR:
+		return 0.1, nil
+	}
+	if rate, ok := r[code]; ok && rate > 0 {
+		return rate, nil
+	}
+	return 0, fmt.Errorf("%w for %s", ErrNoRate, code)
+}
+
+func Parse(data []byte) (Rates, error) {
+	var doc ratesDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	rates := make(Rates, len(doc.Rates))
+	for name, rate := range doc.Rates {
+		code, err := ParseCode(name)
+		if err != nil {
+			continue
+		}
+		rates[code] = rate
+	}
+	return rates, nil
+}
+
+type Source struct {
+	Path       string
+	URL        string
+	MaxAge     time.Duration
+	HTTPClient *http.Client
+}
+
+func (s *Source) Rates(ctx context.Context) (Rates, error) {
+	if s.URL != "" && s.stale() {
+		rates, err := s.fetch(ctx)
+		if err == nil {
+			return rates, nil
+		}
+		if s.Path == "" {
+			return nil, err
+		}
+		if _, statErr := os.Stat(s.Path); statErr != nil {
+			return nil, err
+		}
+	}
+
+	if s.Path == "" {
+		return nil, errors.New("no exchange rate source configured")
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rates: %w", err)
+	}
+	rates, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", s.Path, err)
+	}
+	return rates, nil
+}
+
+func (s *Source) stale() bool {
+	if s.Path == "" {
+		return true
+	}
+	info, err := os.Stat(s.Path)
+	if err != nil {
+		return true
+	}
+	maxAge := s.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+	return time.Since(info.ModTime()) > maxAge
+}
+
+func (s *Source) fetch(ctx context.Context) (Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch rates: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch rates: %w", err)
+	}
+	rates, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rates from %s: %w", s.URL, err)
+	}
+
+	if s.Path != "" {
+		_ = s.save(rates)
+	}
+	return rates, nil
+}
+
+func (s *Source) save(rates Rates) error {
+	doc := ratesDocument{Updated: time.Now().UTC(), Rates: make(map[string]float64, len(rates))}
+	for code, rate := range rates {
+		doc.Rates[strings.ToUpper(string(code))] = rate
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0755); err != nil {
+		return err
+	}
+
+	tmp := s.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.Path)
+}
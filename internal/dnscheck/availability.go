@@ -0,0 +1,58 @@
+package dnscheck
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"domainshell/internal/api"
+	"domainshell/pkg/domain"
+)
+
+func Result(domainName string, registered bool) domain.DomainData {
+	item := domain.DomainData{
+		Domain:    strings.ToLower(domainName),
+		Available: !registered,
+		Source:    domain.SourceDNS,
+	}
+	if registered {
+		item.Reason = "registered (DNS)"
+	}
+	return item
+}
+
+func (c *Checker) Capabilities() api.Capability {
+	return api.CapabilityCheck
+}
+
+func (c *Checker) CheckAvailability(domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (c *Checker) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(ctx, []string{domainName})
+}
+
+func (c *Checker) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
+
+func (c *Checker) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	result := &domain.Response{Data: make([]domain.DomainData, 0, len(domainNames))}
+	for _, name := range domainNames {
+		registered, err := c.Registered(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		result.Data = append(result.Data, Result(name, registered))
+	}
+	return result, nil
+}
+
+func (c *Checker) SuggestDomains(domainName string) (*domain.Response, error) {
+	return nil, fmt.Errorf("dns: suggestions %w", api.ErrUnsupported)
+}
+
+func (c *Checker) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.SuggestDomains(domainName)
+}
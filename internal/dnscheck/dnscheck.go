@@ -0,0 +1,64 @@
+package dnscheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultResolver = "1.1.1.1:53"
+	defaultTimeout  = 3 * time.Second
+)
+
+type Checker struct {
+	resolver string
+	timeout  time.Duration
+	lookup   *net.Resolver
+}
+
+func NewChecker(resolver string, timeout time.Duration) *Checker {
+	if resolver == "" {
+		resolver = DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(resolver); err != nil {
+		resolver = net.JoinHostPort(resolver, "53")
+	}
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	c := &Checker{resolver: resolver, timeout: timeout}
+	c.lookup = &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, c.resolver)
+		},
+	}
+	return c
+}
+
+func (c *Checker) Resolver() string {
+	return c.resolver
+}
+
+func (c *Checker) Registered(ctx context.Context, domainName string) (bool, error) {
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domainName), "."))
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	records, err := c.lookup.LookupNS(ctx, name+".")
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("dns: %w", err)
+	}
+	return len(records) > 0, nil
+}
@@ -0,0 +1,210 @@
+package dnscheck
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	typeNS  = 2
+	classIN = 1
+
+	rcodeSuccess  = 0
+	rcodeNXDomain = 3
+	rcodeServFail = 2
+)
+
+type query struct {
+	name     string
+	qtype    uint16
+	question []byte
+}
+
+func parseQuery(msg []byte) (query, bool) {
+	if len(msg) < 12 {
+		return query{}, false
+	}
+
+	var labels []string
+	off := 12
+	for off < len(msg) && msg[off] != 0 {
+		n := int(msg[off])
+		if off+1+n > len(msg) {
+			return query{}, false
+		}
+		labels = append(labels, string(msg[off+1:off+1+n]))
+		off += 1 + n
+	}
+	if off+5 > len(msg) {
+		return query{}, false
+	}
+
+	return query{
+		name:     strings.ToLower(strings.Join(labels, ".")),
+		qtype:    binary.BigEndian.Uint16(msg[off+1:]),
+		question: msg[12 : off+5],
+	}, true
+}
+
+func encodeName(b []byte, name string) []byte {
+	for _, label := range strings.Split(name, ".") {
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+func buildResponse(id []byte, q query, rcode int, flags uint16, answers int) []byte {
+	resp := append([]byte(nil), id...)
+	resp = binary.BigEndian.AppendUint16(resp, 0x8180|flags|uint16(rcode))
+	resp = binary.BigEndian.AppendUint16(resp, 1)
+	resp = binary.BigEndian.AppendUint16(resp, uint16(answers))
+	resp = binary.BigEndian.AppendUint32(resp, 0)
+	resp = append(resp, q.question...)
+
+	for range answers {
+		rdata := encodeName(nil, "ns1.example.net")
+		resp = append(resp, 0xC0, 12)
+		resp = binary.BigEndian.AppendUint16(resp, typeNS)
+		resp = binary.BigEndian.AppendUint16(resp, classIN)
+		resp = binary.BigEndian.AppendUint32(resp, 300)
+		resp = binary.BigEndian.AppendUint16(resp, uint16(len(rdata)))
+		resp = append(resp, rdata...)
+	}
+	return resp
+}
+
+func startResolver(t *testing.T, delegated map[string]bool, rcodeFor func(name string) int) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 1232)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			q, ok := parseQuery(buf[:n])
+			if !ok {
+				continue
+			}
+
+			rcode := rcodeSuccess
+			if rcodeFor != nil {
+				rcode = rcodeFor(q.name)
+			}
+
+			var flags uint16
+			answers := 0
+			switch {
+			case q.name == "truncated.com":
+				flags = 0x0200
+			case rcode == rcodeSuccess && q.qtype == typeNS && delegated[q.name]:
+				answers = 1
+			}
+
+			_, _ = conn.WriteTo(buildResponse(buf[:2], q, rcode, flags, answers), addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestChecker_Registered(t *testing.T) {
+	resolver := startResolver(t, map[string]bool{"example.com": true}, func(name string) int {
+		if name == "nxdomain.com" {
+			return rcodeNXDomain
+		}
+		if name == "broken.com" {
+			return rcodeServFail
+		}
+		return rcodeSuccess
+	})
+
+	checker := NewChecker(resolver, time.Second)
+
+	tests := []struct {
+		domain      string
+		registered  bool
+		expectError bool
+	}{
+		{domain: "example.com", registered: true},
+		{domain: "Example.COM.", registered: true},
+		{domain: "nxdomain.com", registered: false},
+		{domain: "nodata.com", registered: false},
+		{domain: "broken.com", expectError: true},
+		{domain: "truncated.com", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			registered, err := checker.Registered(context.Background(), tt.domain)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if registered != tt.registered {
+				t.Errorf("Expected registered=%v, got %v", tt.registered, registered)
+			}
+		})
+	}
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	checker := NewChecker(conn.LocalAddr().String(), 50*time.Millisecond)
+	if _, err := checker.Registered(context.Background(), "example.com"); err == nil {
+		t.Error("Expected timeout error from a silent resolver")
+	}
+}
+
+func TestChecker_AsProvider(t *testing.T) {
+	resolver := startResolver(t, map[string]bool{"example.com": true}, nil)
+	checker := NewChecker(resolver, time.Second)
+
+	resp, err := checker.CheckAvailabilityBatch([]string{"example.com", "free-name.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.Data[0].Available || resp.Data[0].Reason != "registered (DNS)" {
+		t.Errorf("Unexpected result for example.com: %+v", resp.Data[0])
+	}
+	if !resp.Data[1].Available {
+		t.Errorf("Unexpected result for free-name.com: %+v", resp.Data[1])
+	}
+	for _, item := range resp.Data {
+		if item.Source != "dns" {
+			t.Errorf("Expected dns source, got %q", item.Source)
+		}
+	}
+}
+
+func TestNewChecker_DefaultPort(t *testing.T) {
+	if r := NewChecker("9.9.9.9", 0).Resolver(); r != "9.9.9.9:53" {
+		t.Errorf("Expected port 53 to be added, got %s", r)
+	}
+	if r := NewChecker("", 0).Resolver(); r != DefaultResolver {
+		t.Errorf("Expected default resolver, got %s", r)
+	}
+}
@@ -2,33 +2,69 @@ package history
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
+const (
+	DefaultLimit   = 1000
+	legacyFileName = "history.txt"
+)
+
+var (
+	ErrNotFound = errors.New("no such history entry")
+	ErrNoEvent  = errors.New("event not found")
+)
+
+type Entry struct {
+	Command    string    `json:"command"`
+	Time       time.Time `json:"time,omitzero"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Outcome    string    `json:"outcome,omitempty"`
+
+	private bool
+}
+
+func (e Entry) Duration() time.Duration {
+	return time.Duration(e.DurationMS) * time.Millisecond
+}
+
+func (e Entry) Failed() bool {
+	return e.Outcome == "error"
+}
+
 type History struct {
+	mu       sync.Mutex
 	filePath string
-	items    []string
+	entries  []Entry
+	limit    int
+	private  bool
 }
 
-func NewHistory() (*History, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
-	}
-
-	historyDir := filepath.Join(homeDir, ".config", "domainshell")
+func NewHistory(path string) (*History, error) {
+	historyDir := filepath.Dir(path)
 	if err := os.MkdirAll(historyDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create history directory: %w", err)
 	}
 
-	filePath := filepath.Join(historyDir, "history.txt")
-
 	h := &History{
-		filePath: filePath,
-		items:    make([]string, 0),
+		filePath: path,
+		entries:  make([]Entry, 0),
+	}
+
+	if err := h.migrate(filepath.Join(historyDir, legacyFileName)); err != nil {
+		return h, fmt.Errorf("failed to migrate %s: %w", legacyFileName, err)
 	}
 
 	if err := h.Load(); err != nil {
@@ -41,83 +77,496 @@ func NewHistory() (*History, error) {
 func NewEmptyHistory() *History {
 	return &History{
 		filePath: "",
-		items:    make([]string, 0),
+		entries:  make([]Entry, 0),
 	}
 }
 
-func (h *History) Load() error {
-	file, err := os.Open(h.filePath)
+func (h *History) migrate(legacyPath string) error {
+	if _, err := os.Stat(h.filePath); !os.IsNotExist(err) {
+		return nil
+	}
+
+	file, err := os.Open(legacyPath)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil
 		}
 		return err
 	}
-	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-		if line != "" {
-			h.items = append(h.items, line)
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.entries = append(h.entries, Entry{Command: line})
 		}
 	}
+	file.Close()
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	if err := h.Save(); err != nil {
+		return err
+	}
+	h.entries = h.entries[:0]
+	if err := os.Rename(legacyPath, legacyPath+".bak"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (h *History) Load() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.filePath == "" {
+		return nil
+	}
+
+	unlock, err := h.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
 
-	return scanner.Err()
+	entries, err := h.readFile()
+	if err != nil {
+		return err
+	}
+
+	h.entries = append(h.entries, entries...)
+	h.trim()
+	return nil
 }
 
 func (h *History) Save() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.filePath == "" {
+		return nil
+	}
+
+	unlock, err := h.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := h.readFile()
+	if err != nil {
+		return err
+	}
+
+	h.entries = merge(current, h.entries)
+	h.trim()
+
+	persisted := slices.DeleteFunc(slices.Clone(h.entries), func(entry Entry) bool { return entry.private })
+	return h.writeFile(persisted)
+}
+
+func (h *History) Add(item string) {
+	h.Record(Entry{Command: item, Time: time.Now()})
+}
+
+func (h *History) Record(entry Entry) {
+	entry.Command = strings.TrimSpace(entry.Command)
+	if entry.Command == "" {
+		return
+	}
+	if !entry.Time.IsZero() {
+		entry.Time = entry.Time.Round(time.Millisecond)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entry.private = h.private
+	h.entries = append(h.entries, entry)
+	h.trim()
+
+	if !h.private {
+		_ = h.append(entry)
+	}
+}
+
+func (h *History) SetPrivate(private bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.private = private
+	if !private {
+		h.entries = slices.DeleteFunc(h.entries, func(entry Entry) bool { return entry.private })
+	}
+}
+
+func (h *History) Private() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.private
+}
+
+func (h *History) append(entry Entry) error {
 	if h.filePath == "" {
 		return nil
 	}
 
-	file, err := os.Create(h.filePath)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := h.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	file, err := os.OpenFile(h.filePath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	lines, err := countLines(h.filePath)
+	if err != nil {
+		return err
+	}
+	if lines > 2*h.capacity() {
+		return h.compact()
+	}
+	return nil
+}
+
+func (h *History) compact() error {
+	entries, err := h.readFile()
 	if err != nil {
 		return err
 	}
+	if limit := h.capacity(); len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return h.writeFile(entries)
+}
+
+func (h *History) lock() (func(), error) {
+	file, err := os.OpenFile(h.filePath+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to lock history: %w", err)
+	}
+
+	return func() {
+		unlockFile(file)
+		file.Close()
+	}, nil
+}
+
+func (h *History) readFile() ([]Entry, error) {
+	file, err := os.Open(h.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			if strings.HasPrefix(line, "{") {
+				continue
+			}
+			entry = Entry{Command: line}
+		}
+		if entry.Command != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+func countLines(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
 	defer file.Close()
 
+	lines := 0
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := file.Read(buf)
+		lines += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+func (h *History) writeFile(entries []Entry) error {
+	tmp := h.filePath + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
 	writer := bufio.NewWriter(file)
-	for _, item := range h.items {
-		if _, err := writer.WriteString(item + "\n"); err != nil {
+	enc := json.NewEncoder(writer)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			file.Close()
 			return err
 		}
 	}
+	if err := writer.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
 
-	return writer.Flush()
+	return os.Rename(tmp, h.filePath)
 }
 
-func (h *History) Add(item string) {
-	item = strings.TrimSpace(item)
-	if item == "" {
-		return
+func merge(current, entries []Entry) []Entry {
+	seen := make(map[Entry]bool, len(current))
+	for _, entry := range current {
+		seen[entry] = true
 	}
 
-	for i, existing := range h.items {
-		if existing == item {
-			h.items = append(h.items[:i], h.items[i+1:]...)
-			break
+	merged := slices.Clone(current)
+	for _, entry := range entries {
+		if !seen[entry] {
+			merged = append(merged, entry)
 		}
 	}
 
-	h.items = append(h.items, item)
+	slices.SortStableFunc(merged, func(a, b Entry) int {
+		return a.Time.Compare(b.Time)
+	})
+	return merged
+}
 
-	if len(h.items) > 1000 {
-		h.items = h.items[len(h.items)-1000:]
+func (h *History) capacity() int {
+	if h.limit <= 0 {
+		return DefaultLimit
 	}
+	return h.limit
+}
+
+func (h *History) trim() {
+	if limit := h.capacity(); len(h.entries) > limit {
+		h.entries = h.entries[len(h.entries)-limit:]
+	}
+}
+
+func (h *History) SetLimit(limit int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
 
-	_ = h.Save()
+	h.limit = limit
 }
 
 func (h *History) GetItems() []string {
-	return h.items
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	last := make(map[string]int, len(h.entries))
+	for i, entry := range h.entries {
+		last[entry.Command] = i
+	}
+
+	items := make([]string, 0, len(last))
+	for i, entry := range h.entries {
+		if last[entry.Command] == i {
+			items = append(items, entry.Command)
+		}
+	}
+	return items
+}
+
+type Numbered struct {
+	Number int
+	Entry
+}
+
+type Filter struct {
+	Since  time.Time
+	Grep   *regexp.Regexp
+	Failed bool
+	Last   int
+}
+
+func (h *History) Entries(filter Filter) []Numbered {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var entries []Numbered
+	for i, entry := range h.entries {
+		if !filter.Since.IsZero() && entry.Time.Before(filter.Since) {
+			continue
+		}
+		if filter.Grep != nil && !filter.Grep.MatchString(entry.Command) {
+			continue
+		}
+		if filter.Failed && !entry.Failed() {
+			continue
+		}
+		entries = append(entries, Numbered{Number: i + 1, Entry: entry})
+	}
+
+	if filter.Last > 0 && len(entries) > filter.Last {
+		entries = entries[len(entries)-filter.Last:]
+	}
+	return entries
+}
+
+func (h *History) Search(re *regexp.Regexp) []Numbered {
+	return h.Entries(Filter{Grep: re})
+}
+
+func (h *History) Get(n int) (Entry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n < 1 || n > len(h.entries) {
+		return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, n)
+	}
+	return h.entries[n-1], nil
+}
+
+func (h *History) Clear() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.entries = h.entries[:0]
+	return h.rewrite(func([]Entry) []Entry { return nil })
+}
+
+func (h *History) Delete(n int) (Entry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if n < 1 || n > len(h.entries) {
+		return Entry{}, fmt.Errorf("%w: %d", ErrNotFound, n)
+	}
+
+	entry := h.entries[n-1]
+	h.entries = slices.Delete(h.entries, n-1, n)
+	return entry, h.rewrite(func(current []Entry) []Entry {
+		for i := len(current) - 1; i >= 0; i-- {
+			if current[i] == entry {
+				return slices.Delete(current, i, i+1)
+			}
+		}
+		return current
+	})
+}
+
+func (h *History) DeleteMatching(re *regexp.Regexp) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	matches := func(entry Entry) bool { return re.MatchString(entry.Command) }
+
+	before := len(h.entries)
+	h.entries = slices.DeleteFunc(h.entries, matches)
+	deleted := before - len(h.entries)
+
+	return deleted, h.rewrite(func(current []Entry) []Entry {
+		return slices.DeleteFunc(current, matches)
+	})
+}
+
+func (h *History) rewrite(apply func([]Entry) []Entry) error {
+	if h.filePath == "" {
+		return nil
+	}
+
+	unlock, err := h.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := h.readFile()
+	if err != nil {
+		return err
+	}
+	return h.writeFile(apply(current))
+}
+
+func (h *History) Expand(line string) (string, bool, error) {
+	event, rest, _ := strings.Cut(line, " ")
+	if !strings.HasPrefix(event, "!") || len(event) < 2 {
+		return line, false, nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	command, ok := h.event(event[1:])
+	if !ok {
+		return line, false, fmt.Errorf("%w: %s", ErrNoEvent, event)
+	}
+	if rest != "" {
+		command += " " + rest
+	}
+	return command, true, nil
+}
+
+func (h *History) event(designator string) (string, bool) {
+	if designator == "!" {
+		designator = "-1"
+	}
+
+	if n, err := strconv.Atoi(designator); err == nil {
+		if n < 0 {
+			n += len(h.entries) + 1
+		}
+		if n < 1 || n > len(h.entries) {
+			return "", false
+		}
+		return h.entries[n-1].Command, true
+	}
+
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if strings.HasPrefix(h.entries[i].Command, designator) {
+			return h.entries[i].Command, true
+		}
+	}
+	return "", false
 }
 
 func (h *History) GetDomains() []string {
 	domains := make([]string, 0)
 	seen := make(map[string]bool)
 
-	for _, item := range h.items {
+	for _, item := range h.GetItems() {
 		parts := strings.Fields(item)
 		if len(parts) > 0 {
 			domain := parts[len(parts)-1]
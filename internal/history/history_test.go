@@ -1,12 +1,27 @@
 package history
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
+func entriesOf(commands ...string) []Entry {
+	entries := make([]Entry, len(commands))
+	for i, command := range commands {
+		entries[i] = Entry{Command: command}
+	}
+	return entries
+}
+
 func TestNewEmptyHistory(t *testing.T) {
 	h := NewEmptyHistory()
 	if h == nil {
@@ -15,18 +30,18 @@ func TestNewEmptyHistory(t *testing.T) {
 	if h.filePath != "" {
 		t.Errorf("Expected empty filePath, got %q", h.filePath)
 	}
-	if len(h.items) != 0 {
-		t.Errorf("Expected empty items, got %d items", len(h.items))
+	if len(h.entries) != 0 {
+		t.Errorf("Expected empty items, got %d items", len(h.entries))
 	}
 }
 
 func TestHistory_Add(t *testing.T) {
 	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, "history.txt")
+	filePath := filepath.Join(tmpDir, "history.jsonl")
 
 	h := &History{
 		filePath: filePath,
-		items:    make([]string, 0),
+		entries:  make([]Entry, 0),
 	}
 
 	tests := []struct {
@@ -64,24 +79,25 @@ func TestHistory_Add(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			h.Add(tt.input)
-			if len(h.items) != tt.expected {
-				t.Errorf("Expected %d items, got %d", tt.expected, len(h.items))
+			if len(h.GetItems()) != tt.expected {
+				t.Errorf("Expected %d items, got %d", tt.expected, len(h.GetItems()))
 			}
 		})
 	}
 
-	if h.items[len(h.items)-1] != "example.com" {
-		t.Errorf("Expected last item to be 'example.com' (duplicate moved to end), got %q", h.items[len(h.items)-1])
+	items := h.GetItems()
+	if items[len(items)-1] != "example.com" {
+		t.Errorf("Expected last item to be 'example.com' (duplicate moved to end), got %q", items[len(items)-1])
 	}
 }
 
 func TestHistory_SaveAndLoad(t *testing.T) {
 	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, "history.txt")
+	filePath := filepath.Join(tmpDir, "history.jsonl")
 
 	h1 := &History{
 		filePath: filePath,
-		items:    []string{"example.com", "example.org", "test.com"},
+		entries:  entriesOf("example.com", "example.org", "test.com"),
 	}
 
 	if err := h1.Save(); err != nil {
@@ -94,27 +110,27 @@ func TestHistory_SaveAndLoad(t *testing.T) {
 
 	h2 := &History{
 		filePath: filePath,
-		items:    make([]string, 0),
+		entries:  make([]Entry, 0),
 	}
 
 	if err := h2.Load(); err != nil {
 		t.Fatalf("Failed to load history: %v", err)
 	}
 
-	if len(h2.items) != len(h1.items) {
-		t.Errorf("Expected %d items after load, got %d", len(h1.items), len(h2.items))
+	if len(h2.entries) != len(h1.entries) {
+		t.Errorf("Expected %d items after load, got %d", len(h1.entries), len(h2.entries))
 	}
 
-	for i, item := range h1.items {
-		if h2.items[i] != item {
-			t.Errorf("Item %d mismatch: expected %q, got %q", i, item, h2.items[i])
+	for i, entry := range h1.entries {
+		if h2.entries[i] != entry {
+			t.Errorf("Item %d mismatch: expected %+v, got %+v", i, entry, h2.entries[i])
 		}
 	}
 }
 
 func TestHistory_GetItems(t *testing.T) {
 	h := &History{
-		items: []string{"item1", "item2", "item3"},
+		entries: entriesOf("item1", "item2", "item3"),
 	}
 
 	items := h.GetItems()
@@ -158,7 +174,7 @@ func TestHistory_GetDomains(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			h := &History{
-				items: tt.items,
+				entries: entriesOf(tt.items...),
 			}
 
 			domains := h.GetDomains()
@@ -182,7 +198,7 @@ func TestHistory_GetDomains(t *testing.T) {
 }
 
 func TestHistory_GetHistoryFilePath(t *testing.T) {
-	filePath := "/tmp/test/history.txt"
+	filePath := "/tmp/test/history.jsonl"
 	h := &History{
 		filePath: filePath,
 	}
@@ -195,7 +211,7 @@ func TestHistory_GetHistoryFilePath(t *testing.T) {
 func TestHistory_SaveWithEmptyPath(t *testing.T) {
 	h := &History{
 		filePath: "",
-		items:    []string{"test"},
+		entries:  entriesOf("test"),
 	}
 
 	if err := h.Save(); err != nil {
@@ -205,41 +221,501 @@ func TestHistory_SaveWithEmptyPath(t *testing.T) {
 
 func TestHistory_LoadNonExistentFile(t *testing.T) {
 	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, "nonexistent.txt")
+	filePath := filepath.Join(tmpDir, "nonexistent.jsonl")
 
 	h := &History{
 		filePath: filePath,
-		items:    make([]string, 0),
+		entries:  make([]Entry, 0),
 	}
 
 	if err := h.Load(); err != nil {
 		t.Errorf("Load() on non-existent file should not return error, got %v", err)
 	}
 
-	if len(h.items) != 0 {
-		t.Errorf("Expected empty items after loading non-existent file, got %d items", len(h.items))
+	if len(h.entries) != 0 {
+		t.Errorf("Expected empty items after loading non-existent file, got %d items", len(h.entries))
 	}
 }
 
 func TestHistory_AddMaxItems(t *testing.T) {
 	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, "history.txt")
+	filePath := filepath.Join(tmpDir, "history.jsonl")
 
 	h := &History{
 		filePath: filePath,
-		items:    make([]string, 0),
+		entries:  make([]Entry, 0),
 	}
 
 	for i := 0; i < 1001; i++ {
 		h.Add(fmt.Sprintf("example%d.com", i))
 	}
 
-	if len(h.items) > 1000 {
-		t.Errorf("Expected max 1000 items, got %d", len(h.items))
+	if len(h.entries) > 1000 {
+		t.Errorf("Expected max 1000 items, got %d", len(h.entries))
+	}
+
+	if len(h.entries) != 1000 {
+		t.Errorf("Expected exactly 1000 items, got %d", len(h.entries))
+	}
+}
+
+func TestHistory_SetLimit(t *testing.T) {
+	h := NewEmptyHistory()
+	h.SetLimit(3)
+
+	for i := 0; i < 5; i++ {
+		h.Add(fmt.Sprintf("example%d.com", i))
+	}
+
+	items := h.GetItems()
+	if len(items) != 3 {
+		t.Fatalf("Expected 3 items, got %d", len(items))
+	}
+	if items[0] != "example2.com" {
+		t.Errorf("Expected the oldest items to be dropped, got %v", items)
+	}
+}
+
+func TestHistory_Migrate(t *testing.T) {
+	tmpDir := t.TempDir()
+	legacyPath := filepath.Join(tmpDir, "history.txt")
+	if err := os.WriteFile(legacyPath, []byte("search example.com\n\nexample.org\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &History{filePath: filepath.Join(tmpDir, "history.jsonl")}
+	if err := h.migrate(legacyPath); err != nil {
+		t.Fatalf("migrate() failed: %v", err)
+	}
+	if _, err := os.Stat(legacyPath); !os.IsNotExist(err) {
+		t.Error("Expected the legacy file to be moved aside")
+	}
+	if _, err := os.Stat(legacyPath + ".bak"); err != nil {
+		t.Errorf("Expected a backup of the legacy file: %v", err)
+	}
+
+	if err := h.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	items := h.GetItems()
+	if len(items) != 2 || items[0] != "search example.com" || items[1] != "example.org" {
+		t.Errorf("Unexpected migrated items: %v", items)
+	}
+
+	if err := h.migrate(legacyPath); err != nil {
+		t.Errorf("Second migrate() should be a no-op, got %v", err)
+	}
+}
+
+func TestHistory_LoadPlainLines(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	content := `{"command":"search example.com","outcome":"taken"}` + "\nexample.org\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &History{filePath: filePath}
+	if err := h.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(h.entries) != 2 || h.entries[0].Outcome != "taken" || h.entries[1].Command != "example.org" {
+		t.Errorf("Unexpected entries: %+v", h.entries)
+	}
+}
+
+func TestHistory_Record(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	h := &History{filePath: filePath}
+	h.Record(Entry{Command: "search example.com", Time: at, DurationMS: 120, Outcome: "available"})
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read history file: %v", err)
+	}
+	expected := `{"command":"search example.com","time":"2026-01-02T03:04:05Z","duration_ms":120,"outcome":"available"}` + "\n"
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+
+	h2 := &History{filePath: filePath}
+	if err := h2.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(h2.entries) != 1 || h2.entries[0].Duration() != 120*time.Millisecond || !h2.entries[0].Time.Equal(at) {
+		t.Errorf("Unexpected entries after load: %+v", h2.entries)
+	}
+}
+
+func TestHistory_Compaction(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h := &History{filePath: filePath, limit: 3}
+	for i := 0; i < 10; i++ {
+		h.Add(fmt.Sprintf("example%d.com", i))
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read history file: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines > 6 {
+		t.Errorf("Expected the file to be compacted to at most 6 lines, got %d", lines)
+	}
+
+	h2 := &History{filePath: filePath, limit: 3}
+	if err := h2.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	items := h2.GetItems()
+	if len(items) != 3 || items[2] != "example9.com" {
+		t.Errorf("Unexpected items after compaction: %v", items)
+	}
+}
+
+func TestHistory_SaveMerges(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	h1 := &History{filePath: filePath}
+	h2 := &History{filePath: filePath}
+	h1.Record(Entry{Command: "search one.com", Time: at})
+	h2.entries = []Entry{{Command: "search two.com", Time: at.Add(time.Minute)}}
+
+	if err := h2.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	h3 := &History{filePath: filePath}
+	if err := h3.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	items := h3.GetItems()
+	if len(items) != 2 || items[0] != "search one.com" || items[1] != "search two.com" {
+		t.Errorf("Expected both sessions' entries, got %v", items)
+	}
+}
+
+func TestHistory_ConcurrentSessions(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	const sessions, commands = 8, 50
+	var wg sync.WaitGroup
+	for s := 0; s < sessions; s++ {
+		wg.Add(1)
+		go func(s int) {
+			defer wg.Done()
+			h := &History{filePath: filePath}
+			for i := 0; i < commands; i++ {
+				h.Add(fmt.Sprintf("search s%d-%d.com", s, i))
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	assertCommands(t, filePath, sessions*commands)
+}
+
+func TestHistory_ConcurrentProcesses(t *testing.T) {
+	if path := os.Getenv("DOMAINSHELL_HISTORY_HELPER"); path != "" {
+		h := &History{filePath: path}
+		for i := 0; i < 50; i++ {
+			h.Add(fmt.Sprintf("search p%d-%d.com", os.Getpid(), i))
+		}
+		return
+	}
+
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	const processes = 4
+	cmds := make([]*exec.Cmd, processes)
+	for i := range cmds {
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^TestHistory_ConcurrentProcesses$")
+		cmds[i].Env = append(os.Environ(), "DOMAINSHELL_HISTORY_HELPER="+filePath)
+		if err := cmds[i].Start(); err != nil {
+			t.Fatalf("Failed to start helper process: %v", err)
+		}
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("Helper process failed: %v", err)
+		}
+	}
+
+	assertCommands(t, filePath, processes*50)
+}
+
+func TestHistory_ConcurrentCompaction(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	var wg sync.WaitGroup
+	for s := 0; s < 4; s++ {
+		wg.Add(1)
+		go func(s int) {
+			defer wg.Done()
+			h := &History{filePath: filePath, limit: 10}
+			for i := 0; i < 100; i++ {
+				h.Add("search s" + strconv.Itoa(s) + ".com")
+			}
+		}(s)
+	}
+	wg.Wait()
+
+	h := &History{filePath: filePath, limit: 10}
+	if err := h.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	lines, err := countLines(filePath)
+	if err != nil {
+		t.Fatalf("Failed to count lines: %v", err)
+	}
+	if len(h.entries) != 10 || lines > 2*10 {
+		t.Errorf("Expected a compacted, readable file, got %d entries in %d lines", len(h.entries), lines)
+	}
+}
+
+func assertCommands(t *testing.T, filePath string, expected int) {
+	t.Helper()
+
+	h := &History{filePath: filePath, limit: expected}
+	if err := h.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(h.entries) != expected {
+		t.Fatalf("Expected %d entries, got %d", expected, len(h.entries))
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range h.entries {
+		if seen[entry.Command] {
+			t.Errorf("Duplicate entry %q", entry.Command)
+		}
+		seen[entry.Command] = true
+	}
+}
+
+func TestHistory_Entries(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	h := &History{
+		entries: []Entry{
+			{Command: "example.com"},
+			{Command: "search example.org", Time: now.Add(-48 * time.Hour), Outcome: "taken"},
+			{Command: "whois example.net", Time: now.Add(-time.Hour), Outcome: "error"},
+			{Command: "search example.io", Time: now, Outcome: "available"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			expected: []string{"example.com", "search example.org", "whois example.net", "search example.io"},
+		},
+		{
+			name:     "since",
+			filter:   Filter{Since: now.Add(-24 * time.Hour)},
+			expected: []string{"whois example.net", "search example.io"},
+		},
+		{
+			name:     "grep",
+			filter:   Filter{Grep: regexp.MustCompile("^search")},
+			expected: []string{"search example.org", "search example.io"},
+		},
+		{
+			name:     "failed",
+			filter:   Filter{Failed: true},
+			expected: []string{"whois example.net"},
+		},
+		{
+			name:   "combined",
+			filter: Filter{Grep: regexp.MustCompile("search"), Failed: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, entry := range h.Entries(tt.filter) {
+				got = append(got, entry.Command)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestHistory_EntriesNumbering(t *testing.T) {
+	h := &History{entries: entriesOf("a.com", "search b.com", "c.com", "search d.com")}
+
+	entries := h.Entries(Filter{Grep: regexp.MustCompile("search"), Last: 1})
+	if len(entries) != 1 || entries[0].Number != 4 || entries[0].Command != "search d.com" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	if matches := h.Search(regexp.MustCompile(`^[ac]\.`)); len(matches) != 2 || matches[1].Number != 3 {
+		t.Errorf("Unexpected search results: %+v", matches)
+	}
+}
+
+func TestHistory_Get(t *testing.T) {
+	h := &History{entries: entriesOf("a.com", "b.com")}
+
+	if entry, err := h.Get(2); err != nil || entry.Command != "b.com" {
+		t.Errorf("Get(2) = %+v, %v", entry, err)
+	}
+	for _, n := range []int{0, 3, -1} {
+		if _, err := h.Get(n); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%d): expected ErrNotFound, got %v", n, err)
+		}
+	}
+}
+
+func TestHistory_Clear(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h := &History{filePath: filePath}
+	h.Add("search example.com")
+	h.Add("example.org")
+
+	if err := h.Clear(); err != nil {
+		t.Fatalf("Clear() failed: %v", err)
+	}
+	if len(h.GetItems()) != 0 {
+		t.Errorf("Expected no items after Clear(), got %v", h.GetItems())
+	}
+
+	h2 := &History{filePath: filePath}
+	if err := h2.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if len(h2.entries) != 0 {
+		t.Errorf("Expected an empty file after Clear(), got %+v", h2.entries)
+	}
+}
+
+func TestHistory_Delete(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h := &History{filePath: filePath}
+	for _, command := range []string{"a.com", "b.com", "a.com"} {
+		h.Record(Entry{Command: command})
+	}
+
+	entry, err := h.Delete(3)
+	if err != nil || entry.Command != "a.com" {
+		t.Fatalf("Delete(3) = %+v, %v", entry, err)
+	}
+	if _, err := h.Delete(3); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	h2 := &History{filePath: filePath}
+	if err := h2.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := h2.GetItems(); strings.Join(got, ",") != "a.com,b.com" {
+		t.Errorf("Expected only the last a.com to be deleted, got %v", got)
+	}
+}
+
+func TestHistory_DeleteMatching(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h := &History{filePath: filePath}
+	other := &History{filePath: filePath}
+	h.Add("search secret.com")
+	other.Add("whois secret.io")
+	h.Add("search public.com")
+
+	deleted, err := h.DeleteMatching(regexp.MustCompile("secret"))
+	if err != nil {
+		t.Fatalf("DeleteMatching() failed: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 deleted entry in this session, got %d", deleted)
+	}
+
+	h2 := &History{filePath: filePath}
+	if err := h2.Load(); err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if got := h2.GetItems(); strings.Join(got, ",") != "search public.com" {
+		t.Errorf("Expected matches from every session to be deleted, got %v", got)
+	}
+}
+
+func TestHistory_Expand(t *testing.T) {
+	h := &History{entries: entriesOf("search example.com", "whois example.org", "check a.com b.com")}
+
+	tests := []struct {
+		name     string
+		line     string
+		expected string
+		expanded bool
+		err      error
+	}{
+		{name: "plain line", line: "search x.com", expected: "search x.com"},
+		{name: "lone bang", line: "!", expected: "!"},
+		{name: "last command", line: "!!", expected: "check a.com b.com", expanded: true},
+		{name: "by number", line: "!1", expected: "search example.com", expanded: true},
+		{name: "relative", line: "!-2", expected: "whois example.org", expanded: true},
+		{name: "by prefix", line: "!wh", expected: "whois example.org", expanded: true},
+		{name: "with arguments", line: "!! c.com", expected: "check a.com b.com c.com", expanded: true},
+		{name: "out of range", line: "!9", expected: "!9", err: ErrNoEvent},
+		{name: "unknown prefix", line: "!zzz", expected: "!zzz", err: ErrNoEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, expanded, err := h.Expand(tt.line)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+			if got != tt.expected || expanded != tt.expanded {
+				t.Errorf("Expand(%q) = %q, %v; expected %q, %v", tt.line, got, expanded, tt.expected, tt.expanded)
+			}
+		})
+	}
+
+	if _, _, err := NewEmptyHistory().Expand("!!"); !errors.Is(err, ErrNoEvent) {
+		t.Errorf("Expected ErrNoEvent on empty history, got %v", err)
+	}
+}
+
+func TestHistory_Private(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	h := &History{filePath: filePath}
+	h.Add("search public.com")
+	h.SetPrivate(true)
+	if !h.Private() {
+		t.Fatal("Expected private mode to be on")
+	}
+	h.Add("search secret.com")
+
+	if got, _, _ := h.Expand("!!"); got != "search secret.com" {
+		t.Errorf("Expected private commands to be usable during the session, got %q", got)
+	}
+	if err := h.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read history file: %v", err)
+	}
+	if strings.Contains(string(data), "secret") {
+		t.Errorf("Expected private commands to stay off disk, got %s", data)
 	}
 
-	if len(h.items) != 1000 {
-		t.Errorf("Expected exactly 1000 items, got %d", len(h.items))
+	h.SetPrivate(false)
+	if got := h.GetItems(); strings.Join(got, ",") != "search public.com" {
+		t.Errorf("Expected private commands to be forgotten, got %v", got)
 	}
 }
 
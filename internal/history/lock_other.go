@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package history
+
+import "os"
+
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
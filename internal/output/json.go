@@ -0,0 +1,148 @@
+package output
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"domainshell/internal/api"
+	"domainshell/internal/results"
+	"domainshell/pkg/domain"
+)
+
+type jsonRenderer struct {
+	w     io.Writer
+	lines bool
+}
+
+type suggestionsDocument struct {
+	Query string              `json:"query"`
+	Data  []domain.DomainData `json:"data"`
+}
+
+type suggestionLine struct {
+	Query string `json:"query"`
+	domain.DomainData
+}
+
+type messageDocument struct {
+	Message string `json:"message"`
+}
+
+type errorDocument struct {
+	Domain string `json:"domain,omitempty"`
+	Error  string `json:"error"`
+	Kind   string `json:"kind,omitempty"`
+	Status int    `json:"status,omitempty"`
+}
+
+func (r *jsonRenderer) Availability(items []domain.DomainData) {
+	if r.lines {
+		for _, item := range items {
+			r.encode(item)
+		}
+		return
+	}
+
+	r.encode(domain.Response{Data: nonNil(items)})
+}
+
+func (r *jsonRenderer) AvailabilityTable(items []domain.DomainData) {
+	r.Availability(items)
+}
+
+type batchDocument struct {
+	Data    []domain.DomainData `json:"data"`
+	Errors  []errorDocument     `json:"errors"`
+	Summary Summary             `json:"summary"`
+}
+
+type summaryLine struct {
+	Summary Summary `json:"summary"`
+}
+
+func (r *jsonRenderer) Batch(items []domain.DomainData, failures []Failure, summary Summary) {
+	errs := make([]errorDocument, len(failures))
+	for i, f := range failures {
+		errs[i] = newErrorDocument(f.Err)
+		errs[i].Domain = f.Domain
+	}
+
+	if r.lines {
+		r.Availability(items)
+		for _, doc := range errs {
+			r.encode(doc)
+		}
+		r.encode(summaryLine{Summary: summary})
+		return
+	}
+
+	r.encode(batchDocument{Data: nonNil(items), Errors: errs, Summary: summary})
+}
+
+func (r *jsonRenderer) Results(items []results.Result) {
+	if r.lines {
+		for _, item := range items {
+			r.encode(item)
+		}
+		return
+	}
+
+	if items == nil {
+		items = []results.Result{}
+	}
+	r.encode(items)
+}
+
+func (r *jsonRenderer) Suggestions(query string, items []domain.DomainData) {
+	if r.lines {
+		for _, item := range items {
+			r.encode(suggestionLine{Query: query, DomainData: item})
+		}
+		return
+	}
+
+	r.encode(suggestionsDocument{Query: query, Data: nonNil(items)})
+}
+
+func (r *jsonRenderer) Fields(title string, fields []Field) {
+	doc := make(map[string]any, len(fields))
+	for _, f := range fields {
+		doc[f.Key] = f.Value
+	}
+	r.encode(doc)
+}
+
+func (r *jsonRenderer) Message(msg string) {
+	r.encode(messageDocument{Message: msg})
+}
+
+func (r *jsonRenderer) Error(err error) {
+	r.encode(newErrorDocument(err))
+}
+
+func newErrorDocument(err error) errorDocument {
+	doc := errorDocument{Error: err.Error()}
+
+	var apiErr *api.Error
+	if errors.As(err, &apiErr) {
+		doc.Kind = apiErr.Kind.Error()
+		doc.Status = apiErr.StatusCode
+	}
+	return doc
+}
+
+func (r *jsonRenderer) encode(v any) {
+	enc := json.NewEncoder(r.w)
+	if !r.lines {
+		enc.SetIndent("", "  ")
+	}
+	_ = enc.Encode(v)
+}
+
+func nonNil(items []domain.DomainData) []domain.DomainData {
+	if items == nil {
+		return []domain.DomainData{}
+	}
+	return items
+}
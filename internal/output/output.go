@@ -0,0 +1,140 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"domainshell/internal/results"
+	"domainshell/pkg/domain"
+)
+
+type Format string
+
+const (
+	FormatTable  Format = "table"
+	FormatPlain  Format = "plain"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+var Formats = []Format{FormatTable, FormatPlain, FormatJSON, FormatNDJSON}
+
+type Field struct {
+	Key   string
+	Value any
+}
+
+type Price int
+
+type Failure struct {
+	Domain string
+	Err    error
+}
+
+type Summary struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+	Taken     int `json:"taken"`
+	Errors    int `json:"errors"`
+}
+
+func (s Summary) fields() []Field {
+	return []Field{
+		{Key: "total", Value: s.Total},
+		{Key: "available", Value: s.Available},
+		{Key: "taken", Value: s.Taken},
+		{Key: "errors", Value: s.Errors},
+	}
+}
+
+type PriceFormatter interface {
+	FormatPrice(amount int) string
+}
+
+type compactToman struct{}
+
+func (compactToman) FormatPrice(amount int) string {
+	return FormatPrice(amount) + " Toman"
+}
+
+var DefaultPriceFormatter PriceFormatter = compactToman{}
+
+type Option func(*options)
+
+type options struct {
+	prices PriceFormatter
+	errors io.Writer
+}
+
+func WithPriceFormatter(f PriceFormatter) Option {
+	return func(o *options) {
+		if f != nil {
+			o.prices = f
+		}
+	}
+}
+
+func WithErrorWriter(w io.Writer) Option {
+	return func(o *options) {
+		o.errors = w
+	}
+}
+
+type Renderer interface {
+	Availability(items []domain.DomainData)
+	AvailabilityTable(items []domain.DomainData)
+	Batch(items []domain.DomainData, failures []Failure, summary Summary)
+	Results(items []results.Result)
+	Suggestions(query string, items []domain.DomainData)
+	Fields(title string, fields []Field)
+	Message(msg string)
+	Error(err error)
+}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q (want %s)", s, formatList())
+}
+
+func New(format Format, w io.Writer, opts ...Option) Renderer {
+	o := options{prices: DefaultPriceFormatter}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.errors == nil {
+		o.errors = w
+	}
+
+	switch format {
+	case FormatPlain:
+		return &plainRenderer{w: w, errors: o.errors}
+	case FormatJSON:
+		return &jsonRenderer{w: w}
+	case FormatNDJSON:
+		return &jsonRenderer{w: w, lines: true}
+	default:
+		return &tableRenderer{w: w, errors: o.errors, prices: o.prices}
+	}
+}
+
+func formatList() string {
+	names := make([]string, len(Formats))
+	for i, f := range Formats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, "|")
+}
+
+func FormatPrice(price int) string {
+	if price >= 1000000 {
+		return fmt.Sprintf("%.2fM", float64(price)/1000000)
+	} else if price >= 1000 {
+		return fmt.Sprintf("%.1fK", float64(price)/1000)
+	}
+	return fmt.Sprintf("%d", price)
+}
@@ -0,0 +1,272 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+func sampleItems() []domain.DomainData {
+	expires := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+	available := domain.DomainData{Available: true, Domain: "example.com", OnSale: true, Premium: true}
+	available.Prices.Register.OneYear = 100000
+
+	return []domain.DomainData{
+		available,
+		{Available: false, Domain: "example.net", Reason: "Already registered", Expires: &expires},
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    Format
+		expectError bool
+	}{
+		{input: "table", expected: FormatTable},
+		{input: "plain", expected: FormatPlain},
+		{input: "JSON", expected: FormatJSON},
+		{input: "ndjson", expected: FormatNDJSON},
+		{input: "xml", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			format, err := ParseFormat(tt.input)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if format != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, format)
+			}
+		})
+	}
+}
+
+func TestJSONRenderer_Availability(t *testing.T) {
+	var buf bytes.Buffer
+	New(FormatJSON, &buf).Availability(sampleItems())
+
+	var response domain.Response
+	if err := json.Unmarshal(buf.Bytes(), &response); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if len(response.Data) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(response.Data))
+	}
+
+	item := response.Data[0]
+	if !item.Available || !item.OnSale || !item.Premium || item.Prices.Register.OneYear != 100000 {
+		t.Errorf("Expected full domain data to round-trip, got %+v", item)
+	}
+}
+
+func TestJSONRenderer_EmptySuggestions(t *testing.T) {
+	var buf bytes.Buffer
+	New(FormatJSON, &buf).Suggestions("example", nil)
+
+	if !strings.Contains(buf.String(), `"data": []`) {
+		t.Errorf("Expected empty data array, got %s", buf.String())
+	}
+}
+
+func TestNDJSONRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(FormatNDJSON, &buf)
+	r.Suggestions("example", sampleItems())
+	r.Error(errors.New("rate limited"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	for i, line := range lines {
+		var v map[string]any
+		if err := json.Unmarshal([]byte(line), &v); err != nil {
+			t.Errorf("Line %d is not valid JSON: %v", i, err)
+		}
+	}
+
+	var first struct {
+		Query string `json:"query"`
+		domain.DomainData
+	}
+	_ = json.Unmarshal([]byte(lines[0]), &first)
+	if first.Query != "example" || first.Domain != "example.com" {
+		t.Errorf("Unexpected first line: %s", lines[0])
+	}
+
+	if !strings.Contains(lines[2], `"error":"rate limited"`) {
+		t.Errorf("Expected error line, got %s", lines[2])
+	}
+}
+
+func TestJSONRenderer_Batch(t *testing.T) {
+	failures := []Failure{{Domain: "broken.ir", Err: errors.New("server error")}}
+	summary := Summary{Total: 3, Available: 1, Taken: 1, Errors: 1}
+
+	var buf bytes.Buffer
+	New(FormatJSON, &buf).Batch(sampleItems(), failures, summary)
+
+	var doc struct {
+		Data   []domain.DomainData `json:"data"`
+		Errors []struct {
+			Domain string `json:"domain"`
+			Error  string `json:"error"`
+		} `json:"errors"`
+		Summary Summary `json:"summary"`
+	}
+	dec := json.NewDecoder(&buf)
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if dec.More() {
+		t.Error("Expected a single JSON document")
+	}
+	if len(doc.Data) != 2 || len(doc.Errors) != 1 || doc.Errors[0].Domain != "broken.ir" || doc.Summary != summary {
+		t.Errorf("Unexpected batch document: %+v", doc)
+	}
+
+	buf.Reset()
+	New(FormatNDJSON, &buf).Batch(sampleItems(), failures, summary)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 lines, got %d: %q", len(lines), buf.String())
+	}
+	if !strings.Contains(lines[2], `"domain":"broken.ir"`) || !strings.HasPrefix(lines[3], `{"summary":`) {
+		t.Errorf("Unexpected ndjson batch output: %q", buf.String())
+	}
+}
+
+func TestPlainRenderer(t *testing.T) {
+	var buf bytes.Buffer
+	New(FormatPlain, &buf).Availability(sampleItems())
+
+	expected := "example.com\tavailable\t100000\ton_sale,premium\t\t\n" +
+		"example.net\ttaken\t0\t\tAlready registered\t2030-01-02\n"
+	if buf.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, buf.String())
+	}
+}
+
+type fixedPrices string
+
+func (f fixedPrices) FormatPrice(amount int) string {
+	return string(f)
+}
+
+func TestRenderer_ErrorWriter(t *testing.T) {
+	tests := []struct {
+		format   Format
+		toStderr bool
+	}{
+		{FormatTable, true},
+		{FormatPlain, true},
+		{FormatJSON, false},
+		{FormatNDJSON, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			New(tt.format, &stdout, WithErrorWriter(&stderr)).Error(errors.New("rate limited"))
+
+			got, other := stdout.String(), stderr.String()
+			if tt.toStderr {
+				got, other = other, got
+			}
+			if !strings.Contains(got, "rate limited") || other != "" {
+				t.Errorf("Unexpected output: stdout %q, stderr %q", stdout.String(), stderr.String())
+			}
+		})
+	}
+}
+
+func TestTableRenderer_PriceFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	r := New(FormatTable, &buf, WithPriceFormatter(fixedPrices("$1.00")))
+	r.Availability(sampleItems()[:1])
+	r.Fields("Price", []Field{{Key: "register", Value: Price(100000)}})
+
+	if strings.Count(buf.String(), "$1.00") != 2 {
+		t.Errorf("Expected the price formatter to be used for every price, got %q", buf.String())
+	}
+
+	buf.Reset()
+	New(FormatPlain, &buf, WithPriceFormatter(fixedPrices("$1.00"))).Fields("", []Field{{Key: "register", Value: Price(100000)}})
+	if buf.String() != "register\t100000\n" {
+		t.Errorf("Expected raw prices in plain output, got %q", buf.String())
+	}
+}
+
+func TestTableRenderer_AvailabilityTable(t *testing.T) {
+	var buf bytes.Buffer
+	New(FormatTable, &buf).AvailabilityTable(sampleItems())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and two rows, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[0], "DOMAIN") || !strings.Contains(lines[1], "100.0K Toman") || !strings.Contains(lines[2], "Already registered") {
+		t.Errorf("Unexpected table: %q", buf.String())
+	}
+	if strings.Index(lines[1], "available") != strings.Index(lines[2], "taken") {
+		t.Errorf("Expected aligned columns, got %q", buf.String())
+	}
+}
+
+func TestFormatPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		price    int
+		expected string
+	}{
+		{
+			name:     "price less than 1000",
+			price:    500,
+			expected: "500",
+		},
+		{
+			name:     "price in thousands",
+			price:    5000,
+			expected: "5.0K",
+		},
+		{
+			name:     "price in millions",
+			price:    1500000,
+			expected: "1.50M",
+		},
+		{
+			name:     "exact thousand",
+			price:    1000,
+			expected: "1.0K",
+		},
+		{
+			name:     "exact million",
+			price:    1000000,
+			expected: "1.00M",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatPrice(tt.price)
+			if result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
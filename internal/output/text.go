@@ -0,0 +1,354 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+
+	"domainshell/internal/results"
+	"domainshell/pkg/domain"
+)
+
+type tableRenderer struct {
+	w      io.Writer
+	errors io.Writer
+	prices PriceFormatter
+}
+
+func (r *tableRenderer) Availability(items []domain.DomainData) {
+	if len(items) == 0 {
+		color.New(color.FgYellow).Fprintln(r.w, "No data returned")
+		return
+	}
+
+	for _, item := range items {
+		r.availability(item)
+	}
+}
+
+func (r *tableRenderer) availability(item domain.DomainData) {
+	white := color.New(color.FgWhite)
+	green := color.New(color.FgGreen, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	yellow := color.New(color.FgYellow)
+
+	if item.Source == domain.SourceDNS {
+		r.dnsAvailability(item)
+		return
+	}
+
+	if item.Available {
+		green.Fprintf(r.w, "%s is available", item.Domain)
+		if item.Prices.Register.OneYear > 0 {
+			white.Fprintf(r.w, " (%s/year", r.price(item.Prices.Register.OneYear))
+			if renew := item.Prices.RenewalPrice(); renew != item.Prices.Register.OneYear {
+				white.Fprintf(r.w, ", renews at %s", r.price(renew))
+			}
+			white.Fprint(r.w, ")")
+		}
+		r.flags(item)
+	} else {
+		red.Fprintf(r.w, "%s is NOT available", item.Domain)
+		if item.Reason != "" {
+			yellow.Fprintf(r.w, " (%s)", item.Reason)
+		}
+		if item.Registrar != "" {
+			white.Fprintf(r.w, " registrar: %s", item.Registrar)
+		}
+		if item.Expires != nil {
+			white.Fprintf(r.w, " expires: %s", item.Expires.Format(time.DateOnly))
+		}
+		r.cached(item)
+	}
+	fmt.Fprintln(r.w)
+}
+
+func (r *tableRenderer) AvailabilityTable(items []domain.DomainData) {
+	r.availabilityTable(items, nil, nil)
+}
+
+func (r *tableRenderer) Batch(items []domain.DomainData, failures []Failure, summary Summary) {
+	r.AvailabilityTable(items)
+	for _, f := range failures {
+		r.Error(fmt.Errorf("%s: %w", f.Domain, f.Err))
+	}
+	r.Fields("Summary", summary.fields())
+}
+
+func (r *tableRenderer) Results(items []results.Result) {
+	data := make([]domain.DomainData, len(items))
+	for i, item := range items {
+		data[i] = item.DomainData
+	}
+	r.availabilityTable(data, []string{"CHECKED", "PROVIDER"}, func(i int) []string {
+		return []string{items[i].CheckedAt.Local().Format("2006-01-02 15:04"), items[i].Provider}
+	})
+}
+
+func (r *tableRenderer) availabilityTable(items []domain.DomainData, extraHeaders []string, extra func(i int) []string) {
+	if len(items) == 0 {
+		color.New(color.FgYellow).Fprintln(r.w, "No data returned")
+		return
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	red := color.New(color.FgRed, color.Bold)
+	magenta := color.New(color.FgMagenta, color.Bold)
+
+	header := append([]string{"DOMAIN", "STATUS", "PRICE"}, extraHeaders...)
+	rows := make([][]string, 0, len(items)+1)
+	rows = append(rows, append(header, "NOTES"))
+	for i, item := range items {
+		status := "taken"
+		if item.Available {
+			status = "available"
+		}
+		if item.Source == domain.SourceDNS {
+			status += " (dns)"
+		}
+
+		price := "-"
+		if item.Prices.Register.OneYear > 0 {
+			price = r.price(item.Prices.Register.OneYear)
+		}
+
+		var notes []string
+		if item.OnSale {
+			notes = append(notes, "on sale")
+		}
+		if item.Premium {
+			notes = append(notes, "premium")
+		}
+		if renew := item.Prices.RenewalPrice(); item.Prices.Register.OneYear > 0 && renew != item.Prices.Register.OneYear {
+			notes = append(notes, "renews at "+r.price(renew))
+		}
+		if item.Reason != "" {
+			notes = append(notes, item.Reason)
+		}
+		if item.Cached {
+			notes = append(notes, "cached")
+		}
+
+		row := []string{item.Domain, status, price}
+		if extra != nil {
+			row = append(row, extra(i)...)
+		}
+		rows = append(rows, append(row, strings.Join(notes, ", ")))
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i := range widths {
+			widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+		}
+	}
+
+	for n, row := range rows {
+		status := color.New(color.FgCyan)
+		switch {
+		case n == 0:
+		case items[n-1].Source == domain.SourceDNS:
+			status = magenta
+		case items[n-1].Available:
+			status = green
+		default:
+			status = red
+		}
+
+		fmt.Fprintf(r.w, "%s  ", pad(row[0], widths[0]))
+		status.Fprint(r.w, pad(row[1], widths[1]))
+		for i := 2; i < len(widths); i++ {
+			fmt.Fprintf(r.w, "  %s", pad(row[i], widths[i]))
+		}
+		fmt.Fprintf(r.w, "  %s\n", row[len(row)-1])
+	}
+}
+
+func pad(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func (r *tableRenderer) dnsAvailability(item domain.DomainData) {
+	magenta := color.New(color.FgMagenta, color.Bold)
+	white := color.New(color.FgWhite)
+
+	if item.Available {
+		magenta.Fprintf(r.w, "%s has no DNS records", item.Domain)
+		white.Fprint(r.w, " (may be available, not confirmed by the registry)")
+	} else {
+		magenta.Fprintf(r.w, "%s is registered (DNS)", item.Domain)
+		white.Fprint(r.w, " (resolves in DNS, not an authoritative answer)")
+	}
+	fmt.Fprintln(r.w)
+}
+
+func (r *tableRenderer) flags(item domain.DomainData) {
+	yellow := color.New(color.FgYellow)
+
+	if item.OnSale {
+		yellow.Fprint(r.w, " [ON SALE]")
+	}
+	if item.Premium {
+		yellow.Fprint(r.w, " [PREMIUM]")
+	}
+	r.cached(item)
+}
+
+func (r *tableRenderer) cached(item domain.DomainData) {
+	if item.Cached {
+		color.New(color.FgHiBlack).Fprint(r.w, " [CACHED]")
+	}
+}
+
+func (r *tableRenderer) price(amount int) string {
+	if r.prices == nil {
+		return DefaultPriceFormatter.FormatPrice(amount)
+	}
+	return r.prices.FormatPrice(amount)
+}
+
+func (r *tableRenderer) Suggestions(query string, items []domain.DomainData) {
+	white := color.New(color.FgWhite)
+	green := color.New(color.FgGreen, color.Bold)
+
+	if len(items) == 0 {
+		color.New(color.FgYellow).Fprintln(r.w, "No suggestions found")
+		return
+	}
+
+	white.Fprintf(r.w, "Suggestions for %s:\n", query)
+	for _, item := range items {
+		if item.Available {
+			green.Fprintf(r.w, "  %s", item.Domain)
+			if item.Prices.Register.OneYear > 0 {
+				white.Fprintf(r.w, " (%s/year)", r.price(item.Prices.Register.OneYear))
+			}
+			r.flags(item)
+			fmt.Fprintln(r.w)
+		}
+	}
+}
+
+func (r *tableRenderer) Fields(title string, fields []Field) {
+	white := color.New(color.FgWhite)
+
+	if title != "" {
+		color.New(color.FgCyan).Fprintf(r.w, "%s:\n", title)
+	}
+
+	width := 0
+	for _, f := range fields {
+		width = max(width, len(f.Key))
+	}
+	for _, f := range fields {
+		value := f.Value
+		if price, ok := value.(Price); ok {
+			value = r.price(int(price))
+		}
+		white.Fprintf(r.w, "  %-*s  %s\n", width, f.Key, formatValue(value))
+	}
+}
+
+func (r *tableRenderer) Message(msg string) {
+	color.New(color.FgWhite).Fprintln(r.w, msg)
+}
+
+func (r *tableRenderer) Error(err error) {
+	color.New(color.FgRed, color.Bold).Fprintf(r.errors, "Error: %v\n", err)
+}
+
+type plainRenderer struct {
+	w      io.Writer
+	errors io.Writer
+}
+
+func (r *plainRenderer) Availability(items []domain.DomainData) {
+	for _, item := range items {
+		r.line(item)
+	}
+}
+
+func (r *plainRenderer) AvailabilityTable(items []domain.DomainData) {
+	r.Availability(items)
+}
+
+func (r *plainRenderer) Batch(items []domain.DomainData, failures []Failure, summary Summary) {
+	r.Availability(items)
+	for _, f := range failures {
+		r.Error(fmt.Errorf("%s: %w", f.Domain, f.Err))
+	}
+	r.Fields("Summary", summary.fields())
+}
+
+func (r *plainRenderer) Results(items []results.Result) {
+	for _, item := range items {
+		status := "taken"
+		if item.Available {
+			status = "available"
+		}
+		fmt.Fprintf(r.w, "%s\t%s\t%d\t%s\t%s\n", item.Domain, status, item.Prices.Register.OneYear, item.CheckedAt.Format(time.RFC3339), item.Provider)
+	}
+}
+
+func (r *plainRenderer) Suggestions(query string, items []domain.DomainData) {
+	for _, item := range items {
+		r.line(item)
+	}
+}
+
+func (r *plainRenderer) line(item domain.DomainData) {
+	status := "taken"
+	if item.Available {
+		status = "available"
+	}
+	if item.Source != "" {
+		status += "-" + item.Source
+	}
+
+	var flags []string
+	if item.OnSale {
+		flags = append(flags, "on_sale")
+	}
+	if item.Premium {
+		flags = append(flags, "premium")
+	}
+	if item.Cached {
+		flags = append(flags, "cached")
+	}
+
+	expires := ""
+	if item.Expires != nil {
+		expires = item.Expires.Format(time.DateOnly)
+	}
+
+	fmt.Fprintf(r.w, "%s\t%s\t%d\t%s\t%s\t%s\n", item.Domain, status, item.Prices.Register.OneYear, strings.Join(flags, ","), item.Reason, expires)
+}
+
+func (r *plainRenderer) Fields(title string, fields []Field) {
+	for _, f := range fields {
+		fmt.Fprintf(r.w, "%s\t%s\n", f.Key, formatValue(f.Value))
+	}
+}
+
+func (r *plainRenderer) Message(msg string) {
+	fmt.Fprintln(r.w, msg)
+}
+
+func (r *plainRenderer) Error(err error) {
+	fmt.Fprintf(r.errors, "error\t%v\n", err)
+}
+
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case []string:
+		return strings.Join(v, ", ")
+	case time.Time:
+		return v.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(v)
+	}
+}
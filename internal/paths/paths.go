@@ -0,0 +1,138 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	appName = "domainshell"
+	EnvHome = "DOMAINSHELL_HOME"
+)
+
+type LookupFunc func(key string) (string, bool)
+
+type Paths struct {
+	Config string
+	Data   string
+	Cache  string
+	State  string
+
+	legacy string
+}
+
+func Resolve(home string, lookup LookupFunc) (Paths, error) {
+	if home == "" {
+		home, _ = lookup(EnvHome)
+	}
+	if home != "" {
+		home, err := filepath.Abs(home)
+		if err != nil {
+			return Paths{}, err
+		}
+		return Paths{Config: home, Data: home, Cache: home, State: home}, nil
+	}
+
+	userHome, _ := lookup("HOME")
+	if userHome == "" {
+		var err error
+		if userHome, err = os.UserHomeDir(); err != nil {
+			return Paths{}, fmt.Errorf("failed to get home directory (set %s or --data-dir): %w", EnvHome, err)
+		}
+	}
+
+	base := func(env string, fallback ...string) string {
+		if dir, ok := lookup(env); ok && filepath.IsAbs(dir) {
+			return filepath.Join(dir, appName)
+		}
+		return filepath.Join(append(append([]string{userHome}, fallback...), appName)...)
+	}
+
+	return Paths{
+		Config: base("XDG_CONFIG_HOME", ".config"),
+		Data:   base("XDG_DATA_HOME", ".local", "share"),
+		Cache:  base("XDG_CACHE_HOME", ".cache"),
+		State:  base("XDG_STATE_HOME", ".local", "state"),
+		legacy: filepath.Join(userHome, ".config", appName),
+	}, nil
+}
+
+func (p Paths) ConfigFile() string {
+	return filepath.Join(p.Config, "config")
+}
+
+func (p Paths) TLDFile() string {
+	return filepath.Join(p.Config, "tlds.json")
+}
+
+func (p Paths) HistoryFile() string {
+	return filepath.Join(p.State, "history.jsonl")
+}
+
+func (p Paths) ResultsFile() string {
+	return filepath.Join(p.Data, "results.jsonl")
+}
+
+func (p Paths) RatesFile() string {
+	return filepath.Join(p.Config, "rates.json")
+}
+
+func (p Paths) RatesCacheFile() string {
+	return filepath.Join(p.Cache, "rates.json")
+}
+
+func (p Paths) ResponseCacheDir(provider string) string {
+	return filepath.Join(p.Cache, "responses", provider)
+}
+
+func (p Paths) Migrate() error {
+	if p.legacy == "" {
+		return nil
+	}
+
+	moves := []struct {
+		name string
+		to   string
+	}{
+		{"config", p.ConfigFile()},
+		{"config.json", filepath.Join(p.Config, "config.json")},
+		{"tlds.json", p.TLDFile()},
+		{"history.jsonl", p.HistoryFile()},
+		{"history.txt", filepath.Join(p.State, "history.txt")},
+		{"history.txt.bak", filepath.Join(p.State, "history.txt.bak")},
+		{"results.jsonl", p.ResultsFile()},
+		{"rates.json", p.RatesFile()},
+		{"rdap-dns.json", filepath.Join(p.Cache, "rdap-dns.json")},
+		{"cache", filepath.Join(p.Cache, "responses")},
+	}
+
+	var errs []error
+	for _, move := range moves {
+		if err := migrate(filepath.Join(p.legacy, move.name), move.to); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func migrate(from, to string) error {
+	if from == to {
+		return nil
+	}
+	if _, err := os.Lstat(from); err != nil {
+		return nil
+	}
+	if _, err := os.Lstat(to); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", from, to, err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func lookupFrom(env map[string]string) LookupFunc {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name     string
+		home     string
+		env      map[string]string
+		expected Paths
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"HOME": "/home/u"},
+			expected: Paths{
+				Config: "/home/u/.config/domainshell",
+				Data:   "/home/u/.local/share/domainshell",
+				Cache:  "/home/u/.cache/domainshell",
+				State:  "/home/u/.local/state/domainshell",
+			},
+		},
+		{
+			name: "xdg variables",
+			env: map[string]string{
+				"HOME":            "/home/u",
+				"XDG_CONFIG_HOME": "/xdg/config",
+				"XDG_DATA_HOME":   "/xdg/data",
+				"XDG_CACHE_HOME":  "/xdg/cache",
+				"XDG_STATE_HOME":  "relative/state",
+			},
+			expected: Paths{
+				Config: "/xdg/config/domainshell",
+				Data:   "/xdg/data/domainshell",
+				Cache:  "/xdg/cache/domainshell",
+				State:  "/home/u/.local/state/domainshell",
+			},
+		},
+		{
+			name:     "domainshell home",
+			env:      map[string]string{"HOME": "/home/u", EnvHome: "/portable", "XDG_DATA_HOME": "/xdg/data"},
+			expected: Paths{Config: "/portable", Data: "/portable", Cache: "/portable", State: "/portable"},
+		},
+		{
+			name:     "data dir override wins",
+			home:     "/flag",
+			env:      map[string]string{"HOME": "/home/u", EnvHome: "/portable"},
+			expected: Paths{Config: "/flag", Data: "/flag", Cache: "/flag", State: "/flag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Resolve(tt.home, lookupFrom(tt.env))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			got.legacy = ""
+			if got != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPaths_Files(t *testing.T) {
+	p := Paths{Config: "/c", Data: "/d", Cache: "/k", State: "/s"}
+
+	for got, expected := range map[string]string{
+		p.ConfigFile():              "/c/config",
+		p.TLDFile():                 "/c/tlds.json",
+		p.HistoryFile():             "/s/history.jsonl",
+		p.ResultsFile():             "/d/results.jsonl",
+		p.RatesFile():               "/c/rates.json",
+		p.RatesCacheFile():          "/k/rates.json",
+		p.ResponseCacheDir("limoo"): "/k/responses/limoo",
+	} {
+		if got != filepath.FromSlash(expected) {
+			t.Errorf("Expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestPaths_Migrate(t *testing.T) {
+	home := t.TempDir()
+	legacy := filepath.Join(home, ".config", "domainshell")
+	if err := os.MkdirAll(filepath.Join(legacy, "cache", "limoo"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"config", "history.jsonl", "results.jsonl", "rates.json", "cache/limoo/check-1.json"} {
+		if err := os.WriteFile(filepath.Join(legacy, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	p, err := Resolve("", lookupFrom(map[string]string{"HOME": home}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := os.MkdirAll(p.Data, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p.ResultsFile(), []byte("newer"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Migrate(); err != nil {
+		t.Fatalf("Migrate() failed: %v", err)
+	}
+
+	for path, expected := range map[string]string{
+		p.ConfigFile():  "config",
+		p.HistoryFile(): "history.jsonl",
+		p.ResultsFile(): "newer",
+		p.RatesFile():   "rates.json",
+		filepath.Join(p.ResponseCacheDir("limoo"), "check-1.json"): "cache/limoo/check-1.json",
+	} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != expected {
+			t.Errorf("%s: expected %q, got %q (%v)", path, expected, data, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(legacy, "history.jsonl")); !os.IsNotExist(err) {
+		t.Error("Expected history to be moved out of the legacy directory")
+	}
+	if _, err := os.Stat(filepath.Join(legacy, "results.jsonl")); err != nil {
+		t.Error("Expected the legacy file to be left alone when the new one exists")
+	}
+
+	portable, _ := Resolve(filepath.Join(home, "portable"), lookupFrom(map[string]string{"HOME": home}))
+	if err := portable.Migrate(); err != nil {
+		t.Errorf("Expected no migration for an explicit data directory, got %v", err)
+	}
+}
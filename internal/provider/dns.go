@@ -0,0 +1,12 @@
+package provider
+
+import (
+	"domainshell/internal/api"
+	"domainshell/internal/dnscheck"
+)
+
+func init() {
+	Register("dns", func(opts Options) (api.ClientInterface, error) {
+		return dnscheck.NewChecker(opts.Resolver, opts.Timeout), nil
+	})
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+
+	"domainshell/internal/api"
+	"domainshell/pkg/domain"
+)
+
+var mockPrices = map[string]int{
+	"com": 1450000,
+	"net": 1650000,
+	"org": 1550000,
+	"io":  4900000,
+	"ir":  90000,
+}
+
+var mockSuggestionTLDs = []string{"com", "net", "org", "io", "ir"}
+
+type mockClient struct{}
+
+func init() {
+	Register("mock", func(opts Options) (api.ClientInterface, error) {
+		return &mockClient{}, nil
+	})
+}
+
+func (m *mockClient) Capabilities() api.Capability {
+	return api.AllCapabilities
+}
+
+func (m *mockClient) CheckAvailability(domainName string) (*domain.Response, error) {
+	return m.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (m *mockClient) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return m.CheckAvailabilityBatchContext(ctx, []string{domainName})
+}
+
+func (m *mockClient) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return m.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
+
+func (m *mockClient) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	result := &domain.Response{Data: make([]domain.DomainData, 0, len(domainNames))}
+	for _, name := range domainNames {
+		result.Data = append(result.Data, mockDomain(strings.ToLower(name)))
+	}
+	return result, nil
+}
+
+func (m *mockClient) SuggestDomains(domainName string) (*domain.Response, error) {
+	return m.SuggestDomainsContext(context.Background(), domainName)
+}
+
+func (m *mockClient) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	label, _, _ := strings.Cut(strings.ToLower(domainName), ".")
+	result := &domain.Response{}
+	for _, tld := range mockSuggestionTLDs {
+		result.Data = append(result.Data, mockDomain(label+"."+tld))
+	}
+	return result, nil
+}
+
+func mockDomain(name string) domain.DomainData {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	sum := h.Sum32()
+
+	item := domain.DomainData{Domain: name, Available: sum%2 == 0}
+	if !item.Available {
+		item.Reason = "Already registered"
+		return item
+	}
+
+	_, tld, _ := strings.Cut(name, ".")
+	item.Prices.Register.OneYear = mockPrices[tld]
+	if item.Prices.Register.OneYear == 0 {
+		item.Prices.Register.OneYear = 2500000
+	}
+	item.Prices.Renew.OneYear = item.Prices.Register.OneYear * 11 / 10
+	item.Prices.Transfer.OneYear = item.Prices.Renew.OneYear
+	item.Prices.Restore.OneYear = item.Prices.Register.OneYear * 5
+	item.Premium = sum%7 == 0
+	item.OnSale = sum%5 == 0
+	return item
+}
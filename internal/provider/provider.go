@@ -0,0 +1,80 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"domainshell/internal/api"
+)
+
+const Default = "limoo"
+
+type Options struct {
+	BaseURL  string
+	CacheDir string
+	Resolver string
+	Timeout  time.Duration
+	Retry    api.RetryPolicy
+	Limiter  *api.RateLimiter
+}
+
+type Factory func(opts Options) (api.ClientInterface, error)
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, exists := factories[name]; exists {
+		panic("provider: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+func New(name string, opts Options) (api.ClientInterface, error) {
+	mu.RLock()
+	factory, ok := factories[strings.ToLower(name)]
+	mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown provider %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return factory(opts)
+}
+
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register("limoo", func(opts Options) (api.ClientInterface, error) {
+		baseURL := opts.BaseURL
+		if baseURL == "" {
+			return api.NewClient(clientOptions(opts)...), nil
+		}
+		return api.NewClientWithBaseURL(baseURL, clientOptions(opts)...), nil
+	})
+}
+
+func clientOptions(opts Options) []api.Option {
+	return []api.Option{
+		api.WithTimeout(opts.Timeout),
+		api.WithRetryPolicy(opts.Retry),
+		api.WithRateLimiter(opts.Limiter),
+	}
+}
@@ -0,0 +1,95 @@
+package provider
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"domainshell/internal/api"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		expectError bool
+	}{
+		{name: "limoo", provider: "limoo"},
+		{name: "case insensitive", provider: "MOCK"},
+		{name: "unknown", provider: "godaddy", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.provider, Options{Retry: api.NoRetry})
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if client == nil {
+				t.Fatal("Expected client, got nil")
+			}
+		})
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	for _, expected := range []string{"limoo", "mock"} {
+		if !slices.Contains(names, expected) {
+			t.Errorf("Expected %q to be registered, got %v", expected, names)
+		}
+	}
+	if !slices.IsSorted(names) {
+		t.Errorf("Expected sorted names, got %v", names)
+	}
+}
+
+func TestRegister_Duplicate(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic on duplicate registration")
+		}
+	}()
+
+	Register("mock", nil)
+}
+
+func TestMockProvider(t *testing.T) {
+	client, err := New("mock", Options{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	first, err := client.CheckAvailabilityBatch([]string{"example.com", "example.net"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, _ := client.CheckAvailabilityBatch([]string{"example.com", "example.net"})
+
+	if len(first.Data) != 2 || first.Data[0].Domain != "example.com" {
+		t.Fatalf("Unexpected data: %+v", first.Data)
+	}
+	for i := range first.Data {
+		if !reflect.DeepEqual(first.Data[i], second.Data[i]) {
+			t.Errorf("Mock results should be deterministic: %+v != %+v", first.Data[i], second.Data[i])
+		}
+	}
+
+	suggestions, err := client.SuggestDomains("example")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(suggestions.Data) != len(mockSuggestionTLDs) {
+		t.Errorf("Expected %d suggestions, got %d", len(mockSuggestionTLDs), len(suggestions.Data))
+	}
+
+	if !api.Supports(client, api.CapabilitySuggest|api.CapabilityPricing) {
+		t.Error("Mock provider should support every capability")
+	}
+}
@@ -0,0 +1,22 @@
+package provider
+
+import (
+	"path/filepath"
+
+	"domainshell/internal/api"
+	"domainshell/internal/rdap"
+)
+
+func init() {
+	Register("rdap", func(opts Options) (api.ClientInterface, error) {
+		rdapOpts := []rdap.Option{
+			rdap.WithTimeout(opts.Timeout),
+			rdap.WithRateLimiter(opts.Limiter),
+			rdap.WithRetryPolicy(opts.Retry),
+		}
+		if opts.CacheDir != "" {
+			rdapOpts = append(rdapOpts, rdap.WithBootstrapCache(filepath.Join(opts.CacheDir, "rdap-dns.json")))
+		}
+		return rdap.NewClient(rdapOpts...)
+	})
+}
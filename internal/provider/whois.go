@@ -0,0 +1,12 @@
+package provider
+
+import (
+	"domainshell/internal/api"
+	"domainshell/internal/whois"
+)
+
+func init() {
+	Register("whois", func(opts Options) (api.ClientInterface, error) {
+		return whois.NewClient(whois.WithTimeout(opts.Timeout)), nil
+	})
+}
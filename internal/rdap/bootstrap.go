@@ -0,0 +1,86 @@
+package rdap
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const IANABootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+//go:embed dns.json
+var bundledBootstrap []byte
+
+type Bootstrap struct {
+	servers map[string][]string
+}
+
+type bootstrapFile struct {
+	Services [][][]string `json:"services"`
+}
+
+func ParseBootstrap(data []byte) (*Bootstrap, error) {
+	var file bootstrapFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse RDAP bootstrap: %w", err)
+	}
+
+	b := &Bootstrap{servers: make(map[string][]string)}
+	for _, service := range file.Services {
+		if len(service) != 2 || len(service[1]) == 0 {
+			continue
+		}
+		for _, tld := range service[0] {
+			b.servers[strings.ToLower(tld)] = service[1]
+		}
+	}
+
+	return b, nil
+}
+
+func BundledBootstrap() *Bootstrap {
+	b, err := ParseBootstrap(bundledBootstrap)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func LoadBootstrap(cachePath string) (*Bootstrap, error) {
+	if cachePath != "" {
+		data, err := os.ReadFile(cachePath)
+		if err == nil {
+			if b, err := ParseBootstrap(data); err == nil {
+				return b, nil
+			}
+			_ = os.Remove(cachePath)
+			return BundledBootstrap(), nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return BundledBootstrap(), nil
+}
+
+func (b *Bootstrap) ServerFor(domainName string) (string, bool) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domainName, ".")), ".")
+	for i := 1; i < len(labels); i++ {
+		if servers, ok := b.servers[strings.Join(labels[i:], ".")]; ok {
+			return pickServer(servers), true
+		}
+	}
+	return "", false
+}
+
+func pickServer(servers []string) string {
+	for _, s := range servers {
+		if strings.HasPrefix(s, "https://") {
+			return s
+		}
+	}
+	return servers[0]
+}
@@ -0,0 +1,257 @@
+package rdap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"domainshell/internal/api"
+	"domainshell/pkg/domain"
+)
+
+const bootstrapMaxAge = 24 * time.Hour
+
+var ErrNoServer = errors.New("no RDAP server known for this TLD")
+
+type Client struct {
+	httpClient   *http.Client
+	timeout      time.Duration
+	limiter      *api.RateLimiter
+	retry        api.RetryPolicy
+	bootstrapURL string
+	cachePath    string
+
+	mu        sync.Mutex
+	bootstrap *Bootstrap
+}
+
+type Option func(*Client)
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+func WithRateLimiter(limiter *api.RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+func WithRetryPolicy(policy api.RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+func WithBootstrap(b *Bootstrap) Option {
+	return func(c *Client) {
+		c.bootstrap = b
+	}
+}
+
+func WithBootstrapCache(path string) Option {
+	return func(c *Client) {
+		c.cachePath = path
+	}
+}
+
+func NewClient(opts ...Option) (*Client, error) {
+	c := &Client{
+		httpClient:   &http.Client{},
+		timeout:      api.DefaultTimeout,
+		retry:        api.DefaultRetryPolicy,
+		bootstrapURL: IANABootstrapURL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.bootstrap == nil {
+		b, err := LoadBootstrap(c.cachePath)
+		if err != nil {
+			return nil, err
+		}
+		c.bootstrap = b
+	}
+
+	return c, nil
+}
+
+func (c *Client) Capabilities() api.Capability {
+	return api.CapabilityCheck
+}
+
+func (c *Client) CheckAvailability(domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (c *Client) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	item, err := c.lookup(ctx, domainName)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Response{Data: []domain.DomainData{*item}}, nil
+}
+
+func (c *Client) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
+
+func (c *Client) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	result := &domain.Response{Data: make([]domain.DomainData, 0, len(domainNames))}
+	for _, name := range domainNames {
+		item, err := c.lookup(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		result.Data = append(result.Data, *item)
+	}
+	return result, nil
+}
+
+func (c *Client) SuggestDomains(domainName string) (*domain.Response, error) {
+	return nil, fmt.Errorf("rdap: suggestions %w", api.ErrUnsupported)
+}
+
+func (c *Client) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.SuggestDomains(domainName)
+}
+
+func (c *Client) RefreshBootstrap(ctx context.Context) error {
+	body, err := c.fetch(ctx, c.bootstrapURL, "application/json")
+	if err != nil {
+		return err
+	}
+
+	b, err := ParseBootstrap(body)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.bootstrap = b
+	c.mu.Unlock()
+
+	if c.cachePath != "" {
+		if err := os.MkdirAll(filepath.Dir(c.cachePath), 0755); err != nil {
+			return fmt.Errorf("failed to cache RDAP bootstrap: %w", err)
+		}
+		if err := os.WriteFile(c.cachePath, body, 0644); err != nil {
+			return fmt.Errorf("failed to cache RDAP bootstrap: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) serverFor(ctx context.Context, domainName string) (string, error) {
+	c.mu.Lock()
+	server, ok := c.bootstrap.ServerFor(domainName)
+	c.mu.Unlock()
+	if ok {
+		return server, nil
+	}
+
+	if c.bootstrapStale() {
+		if err := c.RefreshBootstrap(ctx); err == nil {
+			c.mu.Lock()
+			server, ok = c.bootstrap.ServerFor(domainName)
+			c.mu.Unlock()
+			if ok {
+				return server, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%s: %w", domainName, ErrNoServer)
+}
+
+func (c *Client) bootstrapStale() bool {
+	if c.cachePath == "" {
+		return false
+	}
+	info, err := os.Stat(c.cachePath)
+	return err != nil || time.Since(info.ModTime()) > bootstrapMaxAge
+}
+
+func (c *Client) lookup(ctx context.Context, domainName string) (*domain.DomainData, error) {
+	name := strings.ToLower(strings.TrimSuffix(domainName, "."))
+
+	server, err := c.serverFor(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.fetch(ctx, strings.TrimSuffix(server, "/")+"/domain/"+name, "application/rdap+json")
+	if errors.Is(err, errNotFound) {
+		return &domain.DomainData{Domain: name, Available: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var obj domainObject
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return nil, &api.Error{Kind: api.ErrMalformedResponse, StatusCode: http.StatusOK, Err: err}
+	}
+
+	return obj.toDomainData(name), nil
+}
+
+var errNotFound = errors.New("not found")
+
+func (c *Client) fetch(ctx context.Context, reqURL, accept string) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("request error: %w", err)
+		}
+
+		body, err := c.do(ctx, reqURL, accept)
+		if err == nil || errors.Is(err, errNotFound) || attempt >= c.retry.Attempts() || !c.retry.Retryable(err) || ctx.Err() != nil {
+			return body, err
+		}
+
+		if err := c.retry.Wait(ctx, attempt, err); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) do(ctx context.Context, reqURL, accept string) ([]byte, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, api.ErrorFromResponse(resp)
+	}
+
+	return io.ReadAll(resp.Body)
+}
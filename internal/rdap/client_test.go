@@ -0,0 +1,273 @@
+package rdap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"domainshell/internal/api"
+)
+
+const registeredDomain = `{
+	"objectClassName": "domain",
+	"ldhName": "EXAMPLE.COM",
+	"status": ["client delete prohibited", "client transfer prohibited"],
+	"events": [
+		{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+		{"eventAction": "expiration", "eventDate": "2026-08-13T04:00:00Z"},
+		{"eventAction": "last changed", "eventDate": "2025-08-14T07:01:44Z"}
+	],
+	"entities": [
+		{
+			"objectClassName": "entity",
+			"roles": ["registrar"],
+			"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]
+		}
+	]
+}`
+
+func newRDAPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rdap/domain/example.com":
+			w.Header().Set("Content-Type", "application/rdap+json")
+			fmt.Fprint(w, registeredDomain)
+		case "/rdap/domain/broken.com":
+			fmt.Fprint(w, "<html>")
+		case "/rdap/domain/busy.com":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func testBootstrap(t *testing.T, serverURL string) *Bootstrap {
+	t.Helper()
+
+	b, err := ParseBootstrap([]byte(fmt.Sprintf(`{"services": [[["com", "net"], [%q]]]}`, serverURL+"/rdap/")))
+	if err != nil {
+		t.Fatalf("Failed to parse bootstrap: %v", err)
+	}
+	return b
+}
+
+func TestClient_CheckAvailability(t *testing.T) {
+	server := newRDAPServer(t)
+
+	client, err := NewClient(WithBootstrap(testBootstrap(t, server.URL)))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	resp, err := client.CheckAvailability("Example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	item := resp.Data[0]
+	if item.Available {
+		t.Error("Expected example.com to be registered")
+	}
+	if item.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Expected registrar, got %q", item.Registrar)
+	}
+	if item.Registered == nil || !item.Registered.Equal(time.Date(1995, 8, 14, 4, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected registration date: %v", item.Registered)
+	}
+	if item.Expires == nil || item.Expires.Year() != 2026 {
+		t.Errorf("Unexpected expiry date: %v", item.Expires)
+	}
+	if len(item.Status) != 2 {
+		t.Errorf("Expected 2 statuses, got %v", item.Status)
+	}
+}
+
+func TestClient_NotFoundIsAvailable(t *testing.T) {
+	server := newRDAPServer(t)
+
+	client, _ := NewClient(WithBootstrap(testBootstrap(t, server.URL)))
+
+	resp, err := client.CheckAvailabilityBatch([]string{"example.com", "free-name.net"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(resp.Data) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(resp.Data))
+	}
+	if resp.Data[0].Available || !resp.Data[1].Available {
+		t.Errorf("Unexpected availability: %+v", resp.Data)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	server := newRDAPServer(t)
+
+	client, _ := NewClient(WithBootstrap(testBootstrap(t, server.URL)), WithRetryPolicy(api.NoRetry))
+
+	tests := []struct {
+		domain   string
+		expected error
+	}{
+		{domain: "broken.com", expected: api.ErrMalformedResponse},
+		{domain: "busy.com", expected: api.ErrRateLimited},
+		{domain: "example.ir", expected: ErrNoServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			_, err := client.CheckAvailability(tt.domain)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	if _, err := client.SuggestDomains("example"); !errors.Is(err, api.ErrUnsupported) {
+		t.Errorf("Expected ErrUnsupported for suggestions, got %v", err)
+	}
+	if api.Supports(client, api.CapabilitySuggest) {
+		t.Error("RDAP client should not declare suggestion support")
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		status   int
+		attempts int
+		requests int
+		expected error
+	}{
+		{name: "recovers from rate limit", failures: 2, status: http.StatusTooManyRequests, attempts: 3, requests: 3},
+		{name: "recovers from server error", failures: 1, status: http.StatusBadGateway, attempts: 3, requests: 2},
+		{name: "gives up", failures: 5, status: http.StatusServiceUnavailable, attempts: 3, requests: 3, expected: api.ErrServerError},
+		{name: "client errors are final", failures: 5, status: http.StatusForbidden, attempts: 3, requests: 1, expected: api.ErrUnexpectedStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				if requests <= tt.failures {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			}))
+			defer server.Close()
+
+			client, _ := NewClient(
+				WithBootstrap(testBootstrap(t, server.URL)),
+				WithRetryPolicy(api.RetryPolicy{MaxAttempts: tt.attempts}),
+			)
+
+			resp, err := client.CheckAvailability("free.com")
+			if !errors.Is(err, tt.expected) || (err == nil && !resp.Data[0].Available) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+			if requests != tt.requests {
+				t.Errorf("Expected %d requests, got %d", tt.requests, requests)
+			}
+		})
+	}
+}
+
+func TestClient_RefreshesStaleBootstrap(t *testing.T) {
+	rdapServer := newRDAPServer(t)
+	bootstrapServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"services": [[["org"], [%q]]]}`, rdapServer.URL+"/rdap/")
+	}))
+	defer bootstrapServer.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "rdap-dns.json")
+	client, err := NewClient(WithBootstrapCache(cachePath))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	client.bootstrapURL = bootstrapServer.URL
+
+	if _, err := client.CheckAvailabilityContext(context.Background(), "free-name.ir"); !errors.Is(err, ErrNoServer) {
+		t.Fatalf("Expected ErrNoServer, got %v", err)
+	}
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("Expected refreshed bootstrap to be cached: %v", err)
+	}
+
+	cached, err := LoadBootstrap(cachePath)
+	if err != nil {
+		t.Fatalf("Failed to load cached bootstrap: %v", err)
+	}
+	if server, ok := cached.ServerFor("example.org"); !ok || server != rdapServer.URL+"/rdap/" {
+		t.Errorf("Unexpected cached server %q", server)
+	}
+}
+
+func TestLoadBootstrap_CorruptCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "rdap-dns.json")
+	if err := os.WriteFile(cachePath, []byte(`{"services": [[["com"], [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient(WithBootstrapCache(cachePath))
+	if err != nil {
+		t.Fatalf("Expected the bundled bootstrap for a corrupt cache, got %v", err)
+	}
+	if _, ok := client.bootstrap.ServerFor("example.com"); !ok {
+		t.Error("Expected the bundled bootstrap to cover .com")
+	}
+	if !client.bootstrapStale() {
+		t.Error("Expected a corrupt cache to be downloaded again")
+	}
+}
+
+func TestBootstrap_ServerFor(t *testing.T) {
+	b, err := ParseBootstrap([]byte(`{"services": [
+		[["uk"], ["http://rdap.example/uk/", "https://rdap.example/uk/"]],
+		[["co.uk"], ["https://rdap.example/co.uk/"]]
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		domain   string
+		expected string
+		found    bool
+	}{
+		{domain: "example.uk", expected: "https://rdap.example/uk/", found: true},
+		{domain: "example.co.uk", expected: "https://rdap.example/co.uk/", found: true},
+		{domain: "EXAMPLE.UK.", expected: "https://rdap.example/uk/", found: true},
+		{domain: "example.com", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			server, ok := b.ServerFor(tt.domain)
+			if ok != tt.found || server != tt.expected {
+				t.Errorf("Expected (%q, %v), got (%q, %v)", tt.expected, tt.found, server, ok)
+			}
+		})
+	}
+}
+
+func TestBundledBootstrap(t *testing.T) {
+	b := BundledBootstrap()
+	if _, ok := b.ServerFor("example.com"); !ok {
+		t.Error("Bundled bootstrap should cover .com")
+	}
+}
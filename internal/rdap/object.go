@@ -0,0 +1,77 @@
+package rdap
+
+import (
+	"strings"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+type domainObject struct {
+	LDHName  string   `json:"ldhName"`
+	Status   []string `json:"status"`
+	Events   []event  `json:"events"`
+	Entities []entity `json:"entities"`
+}
+
+type event struct {
+	Action string    `json:"eventAction"`
+	Date   time.Time `json:"eventDate"`
+}
+
+type entity struct {
+	Roles      []string `json:"roles"`
+	VCardArray []any    `json:"vcardArray"`
+}
+
+func (o *domainObject) toDomainData(name string) *domain.DomainData {
+	item := &domain.DomainData{
+		Domain:    name,
+		Available: false,
+		Reason:    "Registered",
+		Status:    o.Status,
+	}
+
+	for _, e := range o.Events {
+		date := e.Date
+		switch e.Action {
+		case "registration":
+			item.Registered = &date
+		case "expiration":
+			item.Expires = &date
+		}
+	}
+
+	for _, ent := range o.Entities {
+		for _, role := range ent.Roles {
+			if role == "registrar" {
+				item.Registrar = ent.formattedName()
+			}
+		}
+	}
+
+	return item
+}
+
+func (e *entity) formattedName() string {
+	if len(e.VCardArray) < 2 {
+		return ""
+	}
+
+	props, ok := e.VCardArray[1].([]any)
+	if !ok {
+		return ""
+	}
+
+	for _, p := range props {
+		prop, ok := p.([]any)
+		if !ok || len(prop) < 4 {
+			continue
+		}
+		if name, _ := prop[0].(string); strings.EqualFold(name, "fn") {
+			value, _ := prop[3].(string)
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,188 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"domainshell/internal/history"
+	"domainshell/internal/output"
+	"domainshell/internal/since"
+)
+
+const historyUsage = "history [-n <count>] [--since <when>] [--grep <regex>] [--failed] | history clear | history delete <n|regex> | history search <regex>"
+
+func completeHistory(args []string) []string {
+	if len(args) == 0 {
+		return []string{"clear", "delete", "search", "--since", "--grep", "--failed"}
+	}
+	return []string{"--since", "--grep", "--failed"}
+}
+
+func (r *REPL) runHistory(ctx context.Context, args string) error {
+	fields := strings.Fields(args)
+	if len(fields) > 0 {
+		switch fields[0] {
+		case "clear":
+			if len(fields) != 1 {
+				return r.cmds.Usage("history clear")
+			}
+			return r.clearHistory()
+		case "delete":
+			if len(fields) != 2 {
+				return r.cmds.Usage("history delete <n|regex>")
+			}
+			return r.deleteHistory(fields[1])
+		case "search":
+			if len(fields) != 2 {
+				return r.cmds.Usage("history search <regex>")
+			}
+			re, err := compilePattern(fields[1])
+			if err != nil {
+				return r.cmds.Fail(err)
+			}
+			r.printHistory(r.hist.Search(re))
+			return nil
+		}
+	}
+
+	return r.showHistory(fields)
+}
+
+func (r *REPL) showHistory(fields []string) error {
+	var filter history.Filter
+	filtered := false
+
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		value := ""
+		if name, v, ok := strings.Cut(field, "="); ok && strings.HasPrefix(name, "-") {
+			field, value = name, v
+		} else if (field == "-n" || field == "--since" || field == "--grep") && i+1 < len(fields) {
+			value = fields[i+1]
+			i++
+		}
+
+		switch field {
+		case "-n":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return r.cmds.Usage(historyUsage)
+			}
+			filter.Last = n
+			continue
+		case "--since":
+			from, err := since.Parse(value, time.Now())
+			if err != nil {
+				return r.cmds.Fail(err)
+			}
+			filter.Since = from
+		case "--grep":
+			if value == "" {
+				return r.cmds.Usage(historyUsage)
+			}
+			re, err := compilePattern(value)
+			if err != nil {
+				return r.cmds.Fail(err)
+			}
+			filter.Grep = re
+		case "--failed":
+			filter.Failed = true
+		default:
+			return r.cmds.Usage(historyUsage)
+		}
+		filtered = true
+	}
+
+	if filter.Last == 0 && !filtered {
+		filter.Last = r.cmds.Config().HistoryView
+	}
+
+	r.printHistory(r.hist.Entries(filter))
+	return nil
+}
+
+func (r *REPL) printHistory(entries []history.Numbered) {
+	if len(entries) == 0 {
+		r.cmds.Output().Message("No history")
+		return
+	}
+
+	fields := make([]output.Field, len(entries))
+	for i, entry := range entries {
+		fields[i] = output.Field{Key: strconv.Itoa(entry.Number), Value: formatEntry(entry.Entry)}
+	}
+	r.cmds.Output().Fields("", fields)
+}
+
+func (r *REPL) clearHistory() error {
+	if err := r.hist.Clear(); err != nil {
+		return r.cmds.Fail(err)
+	}
+	r.loadReadlineHistory()
+	r.cmds.Output().Message("History cleared")
+	return nil
+}
+
+func (r *REPL) deleteHistory(target string) error {
+	if n, err := strconv.Atoi(target); err == nil {
+		entry, err := r.hist.Delete(n)
+		if err != nil {
+			return r.cmds.Fail(err)
+		}
+		r.loadReadlineHistory()
+		r.cmds.Output().Message(fmt.Sprintf("Deleted %d: %s", n, entry.Command))
+		return nil
+	}
+
+	re, err := compilePattern(target)
+	if err != nil {
+		return r.cmds.Fail(err)
+	}
+	deleted, err := r.hist.DeleteMatching(re)
+	if err != nil {
+		return r.cmds.Fail(err)
+	}
+	r.loadReadlineHistory()
+	r.cmds.Output().Message(fmt.Sprintf("Deleted %d entries", deleted))
+	return nil
+}
+
+func (r *REPL) loadReadlineHistory() {
+	r.rl.ResetHistory()
+	for _, item := range r.hist.GetItems() {
+		r.rl.SaveHistory(item)
+	}
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	return re, nil
+}
+
+func formatEntry(entry history.Entry) string {
+	when := strings.Repeat(" ", len("2006-01-02 15:04"))
+	if !entry.Time.IsZero() {
+		when = entry.Time.Local().Format("2006-01-02 15:04")
+	}
+
+	var details []string
+	if entry.DurationMS > 0 {
+		details = append(details, entry.Duration().String())
+	}
+	if entry.Outcome != "" {
+		details = append(details, entry.Outcome)
+	}
+
+	line := when + "  " + entry.Command
+	if len(details) > 0 {
+		line += "  (" + strings.Join(details, ", ") + ")"
+	}
+	return line
+}
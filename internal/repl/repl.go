@@ -1,8 +1,13 @@
 package repl
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
+	"os/signal"
 	"strings"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/fatih/color"
@@ -11,24 +16,18 @@ import (
 	"domainshell/internal/history"
 )
 
+var errExit = errors.New("exit")
+
 type REPL struct {
-	cmds  *commands.Commands
-	hist  *history.History
-	rl    *readline.Instance
-	white *color.Color
+	cmds *commands.Commands
+	hist *history.History
+	rl   *readline.Instance
 }
 
 func NewREPL(cmds *commands.Commands, hist *history.History) (*REPL, error) {
-	white := color.New(color.FgWhite)
-
-	historyFile := ""
-	if hist != nil {
-		historyFile = hist.GetHistoryFilePath()
-	}
-
 	rl, err := readline.NewEx(&readline.Config{
-		Prompt:            "domain → ",
-		HistoryFile:       historyFile,
+		Prompt:            cmds.Config().Prompt,
+		HistoryLimit:      cmds.Config().HistorySize,
 		AutoComplete:      nil,
 		InterruptPrompt:   "^C",
 		EOFPrompt:         "exit",
@@ -38,16 +37,32 @@ func NewREPL(cmds *commands.Commands, hist *history.History) (*REPL, error) {
 		return nil, fmt.Errorf("failed to initialize readline: %w", err)
 	}
 
-	completer := &Completer{hist: hist}
-	rl.Config.AutoComplete = completer
-
 	r := &REPL{
-		cmds:  cmds,
-		hist:  hist,
-		rl:    rl,
-		white: white,
+		cmds: cmds,
+		hist: hist,
+		rl:   rl,
+	}
+
+	cmds.Register(commands.NewCommand("history", historyUsage, "Show, search or edit command history", r.runHistory,
+		commands.WithCompleter(completeHistory)))
+	cmds.Register(commands.NewCommand("incognito", "incognito [on|off]", "Keep history, cache and results in memory only", r.runIncognito,
+		commands.WithCompleter(func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return []string{"on", "off"}
+		})))
+	cmds.Register(commands.NewCommand("exit", "exit, quit", "Exit the program",
+		func(ctx context.Context, args string) error { return errExit },
+		commands.WithAliases("quit")))
+	if hist != nil {
+		cmds.SetDomainSource(hist.GetDomains)
+		hist.SetPrivate(cmds.Private())
+		r.loadReadlineHistory()
 	}
 
+	rl.Config.AutoComplete = &Completer{cmds: cmds, hist: hist}
+
 	return r, nil
 }
 
@@ -55,6 +70,10 @@ func (r *REPL) Run() error {
 	defer r.rl.Close()
 
 	for {
+		settings := r.cmds.Config()
+		r.rl.SetPrompt(r.prompt(settings.Prompt))
+		r.hist.SetLimit(settings.HistorySize)
+
 		line, err := r.rl.Readline()
 		if err != nil {
 			if err == readline.ErrInterrupt {
@@ -68,65 +87,85 @@ func (r *REPL) Run() error {
 			continue
 		}
 
-		r.hist.Add(line)
-
-		command, args := commands.ParseInput(line)
+		expanded, ok, err := r.hist.Expand(line)
+		if err != nil {
+			r.cmds.Fail(err)
+			continue
+		}
+		if ok {
+			line = expanded
+			r.cmds.Output().Message(line)
+		}
 
-		switch command {
-		case "exit", "quit":
+		command, args := r.cmds.ParseInput(line)
+		started := time.Now()
+		err = r.run(command, args)
+		r.record(line, started, err)
+		if errors.Is(err, errExit) {
 			return nil
-		case "help":
-			r.cmds.Help()
-		case "history":
-			r.showHistory()
-		case "search":
-			if args == "" {
-				r.white.Println("Usage: search <domain>")
-				continue
-			}
-			_ = r.cmds.Search(args)
-		case "suggest":
-			if args == "" {
-				r.white.Println("Usage: suggest <domain>")
-				continue
-			}
-			_ = r.cmds.Suggest(args)
-		default:
-			if args == "" && command != "" {
-				_ = r.cmds.Search(command)
-			}
 		}
 	}
 
 	return nil
 }
 
-func (r *REPL) showHistory() {
-	items := r.hist.GetItems()
-	if len(items) == 0 {
-		r.white.Println("No history")
-		return
+func (r *REPL) prompt(prompt string) string {
+	if r.cmds.Private() {
+		return color.New(color.FgMagenta).Sprint("[incognito] ") + prompt
 	}
+	return prompt
+}
 
-	start := 0
-	if len(items) > 20 {
-		start = len(items) - 20
+func (r *REPL) runIncognito(ctx context.Context, args string) error {
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		state := "off"
+		if r.cmds.Private() {
+			state = "on"
+		}
+		r.cmds.Output().Message("Incognito mode is " + state)
+	case "on":
+		r.cmds.SetPrivate(true)
+		r.hist.SetPrivate(true)
+		r.cmds.Output().Message("Incognito mode on: history, cache and results stay in memory until you turn it off")
+	case "off":
+		r.cmds.SetPrivate(false)
+		r.hist.SetPrivate(false)
+		r.loadReadlineHistory()
+		r.cmds.Output().Message("Incognito mode off: this session's private commands and results were forgotten")
+	default:
+		return r.cmds.Usage("incognito [on|off]")
 	}
+	return nil
+}
+
+func (r *REPL) run(command, args string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	for _, item := range items[start:] {
-		r.white.Println("  ", item)
+	return r.cmds.Run(ctx, command, args)
+}
+
+func (r *REPL) record(line string, started time.Time, err error) {
+	outcome := r.cmds.LastOutcome()
+	if err != nil && !errors.Is(err, errExit) {
+		outcome = commands.OutcomeError
 	}
+
+	r.hist.Record(history.Entry{
+		Command:    line,
+		Time:       started,
+		DurationMS: time.Since(started).Milliseconds(),
+		Outcome:    outcome.String(),
+	})
 }
 
 type Completer struct {
+	cmds *commands.Commands
 	hist *history.History
 }
 
 func (c *Completer) Do(line []rune, pos int) (newLine [][]rune, length int) {
-	if c.hist == nil {
-		return nil, 0
-	}
-
 	if pos == 0 {
 		return nil, 0
 	}
@@ -142,37 +181,22 @@ func (c *Completer) Do(line []rune, pos int) (newLine [][]rune, length int) {
 	}
 
 	prefixLower := strings.ToLower(prefix)
-	text := string(line[:pos])
-	parts := strings.Fields(text)
-
-	var candidates []string
-	commands := []string{"search", "suggest", "help", "history", "exit", "quit"}
+	parts := strings.Fields(string(line[:start]))
 
-	if len(parts) == 0 || (len(parts) == 1 && strings.HasPrefix(text, prefix)) {
-		for _, cmd := range commands {
-			if strings.HasPrefix(cmd, prefixLower) {
-				candidates = append(candidates, cmd)
-			}
-		}
+	var options []string
+	if len(parts) == 0 {
+		options = c.cmds.Registry().Names()
 		if c.hist != nil {
-			domains := c.hist.GetDomains()
-			for _, domain := range domains {
-				if strings.HasPrefix(strings.ToLower(domain), prefixLower) {
-					candidates = append(candidates, domain)
-				}
-			}
+			options = append(options, c.hist.GetDomains()...)
 		}
-	} else if len(parts) > 1 {
-		firstCmd := strings.ToLower(parts[0])
-		if firstCmd == "search" || firstCmd == "suggest" {
-			if c.hist != nil {
-				domains := c.hist.GetDomains()
-				for _, domain := range domains {
-					if strings.HasPrefix(strings.ToLower(domain), prefixLower) {
-						candidates = append(candidates, domain)
-					}
-				}
-			}
+	} else if cmd, ok := c.cmds.Registry().Lookup(parts[0]); ok {
+		options = cmd.Complete(parts[1:])
+	}
+
+	var candidates []string
+	for _, option := range options {
+		if strings.HasPrefix(strings.ToLower(option), prefixLower) {
+			candidates = append(candidates, option)
 		}
 	}
 
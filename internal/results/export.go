@@ -0,0 +1,167 @@
+package results
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+)
+
+var Formats = []Format{FormatCSV, FormatJSON, FormatMarkdown}
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "csv":
+		return FormatCSV, nil
+	case "json":
+		return FormatJSON, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown export format %q (want csv|json|markdown)", s)
+}
+
+func Write(w io.Writer, format Format, items []Result) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if items == nil {
+			items = []Result{}
+		}
+		return enc.Encode(items)
+	case FormatCSV:
+		return writeCSV(w, items)
+	case FormatMarkdown:
+		return writeMarkdown(w, items)
+	}
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+type column struct {
+	name  string
+	value func(Result) string
+}
+
+func columns(items []Result) []column {
+	cols := []column{
+		{"domain", func(r Result) string { return r.Domain }},
+		{"available", func(r Result) string { return strconv.FormatBool(r.Available) }},
+		{"on_sale", func(r Result) string { return strconv.FormatBool(r.OnSale) }},
+		{"premium", func(r Result) string { return strconv.FormatBool(r.Premium) }},
+	}
+
+	kinds := []struct {
+		name   string
+		prices func(domain.Prices) domain.PeriodPrices
+	}{
+		{"register", func(p domain.Prices) domain.PeriodPrices { return p.Register }},
+		{"renew", func(p domain.Prices) domain.PeriodPrices { return p.Renew }},
+		{"transfer", func(p domain.Prices) domain.PeriodPrices { return p.Transfer }},
+		{"restore", func(p domain.Prices) domain.PeriodPrices { return p.Restore }},
+	}
+	for _, kind := range kinds {
+		for years := 1; years <= domain.MaxYears; years++ {
+			used := kind.name == "register" && years == 1
+			for _, item := range items {
+				if kind.prices(item.Prices).For(years) > 0 {
+					used = true
+					break
+				}
+			}
+			if !used {
+				continue
+			}
+			cols = append(cols, column{fmt.Sprintf("%s_%dy", kind.name, years), func(r Result) string {
+				return strconv.Itoa(kind.prices(r.Prices).For(years))
+			}})
+		}
+	}
+
+	return append(cols,
+		column{"reason", func(r Result) string { return r.Reason }},
+		column{"cached", func(r Result) string { return strconv.FormatBool(r.Cached) }},
+		column{"registered", func(r Result) string { return formatDate(r.Registered) }},
+		column{"expires", func(r Result) string { return formatDate(r.Expires) }},
+		column{"registrar", func(r Result) string { return r.Registrar }},
+		column{"status", func(r Result) string { return strings.Join(r.Status, ";") }},
+		column{"source", func(r Result) string { return r.Source }},
+		column{"checked_at", func(r Result) string { return r.CheckedAt.Format(time.RFC3339) }},
+		column{"provider", func(r Result) string { return r.Provider }},
+	)
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func writeCSV(w io.Writer, items []Result) error {
+	cols := columns(items)
+	cw := csv.NewWriter(w)
+
+	row := make([]string, len(cols))
+	for i, col := range cols {
+		row[i] = col.name
+	}
+	if err := cw.Write(row); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		for i, col := range cols {
+			row[i] = col.value(item)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMarkdown(w io.Writer, items []Result) error {
+	cols := columns(items)
+
+	names := make([]string, len(cols))
+	rule := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.name
+		rule[i] = "---"
+	}
+	if _, err := fmt.Fprintf(w, "| %s |\n| %s |\n", strings.Join(names, " | "), strings.Join(rule, " | ")); err != nil {
+		return err
+	}
+
+	cells := make([]string, len(cols))
+	for _, item := range items {
+		for i, col := range cols {
+			cells[i] = markdownEscape(col.value(item))
+		}
+		if _, err := fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func markdownEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
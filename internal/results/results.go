@@ -0,0 +1,83 @@
+package results
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+type Result struct {
+	domain.DomainData
+	CheckedAt time.Time `json:"checked_at"`
+	Provider  string    `json:"provider"`
+
+	private bool
+}
+
+func New(item domain.DomainData, provider string, at time.Time) Result {
+	if item.Source != "" {
+		provider = item.Source
+	}
+	if !item.CachedAt.IsZero() {
+		at = item.CachedAt
+	}
+	return Result{DomainData: item, CheckedAt: at.UTC().Truncate(time.Second), Provider: provider}
+}
+
+type Session struct {
+	mu      sync.Mutex
+	items   []Result
+	private bool
+}
+
+func NewSession() *Session {
+	return &Session{}
+}
+
+func (s *Session) Add(items ...Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, item := range items {
+		item.private = s.private
+		s.items = append(s.items, item)
+	}
+}
+
+func (s *Session) SetPrivate(private bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.private = private
+	if !private {
+		s.items = slices.DeleteFunc(s.items, func(item Result) bool { return item.private })
+	}
+}
+
+func (s *Session) All() []Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Result(nil), s.items...)
+}
+
+func ReadFile(path string) ([]Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return Read(f)
+}
+
+func Read(r io.Reader) ([]Result, error) {
+	var items []Result
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	return items, nil
+}
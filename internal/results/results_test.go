@@ -0,0 +1,266 @@
+package results
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"domainshell/pkg/domain"
+)
+
+func sampleResults() []Result {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	expires := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	free := domain.DomainData{Available: true, Domain: "example.com", Premium: true}
+	free.Prices.Register.OneYear = 100000
+	free.Prices.Register.TwoYears = 190000
+
+	return []Result{
+		New(free, "limoo", at),
+		New(domain.DomainData{Domain: "example.net", Reason: "Already | registered", Expires: &expires, Status: []string{"active", "locked"}}, "limoo", at),
+		New(domain.DomainData{Domain: "example.org", Source: domain.SourceDNS}, "limoo", at),
+	}
+}
+
+func TestNew_Provider(t *testing.T) {
+	items := sampleResults()
+	if items[0].Provider != "limoo" {
+		t.Errorf("Expected limoo, got %q", items[0].Provider)
+	}
+	if items[2].Provider != domain.SourceDNS {
+		t.Errorf("Expected the DNS pre-check to be reported as the provider, got %q", items[2].Provider)
+	}
+}
+
+func TestNew_CheckedAt(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	stored := now.Add(-45 * time.Minute)
+
+	tests := []struct {
+		name string
+		item domain.DomainData
+		want time.Time
+	}{
+		{"fresh", domain.DomainData{Domain: "example.com"}, now},
+		{"cached", domain.DomainData{Domain: "example.com", Cached: true, CachedAt: stored}, stored},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(tt.item, "limoo", now).CheckedAt; !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestWrite_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, sampleResults()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected a header and 3 rows, got %d", len(records))
+	}
+
+	header := strings.Join(records[0], ",")
+	for _, name := range []string{"domain", "register_1y", "register_2y", "expires", "status", "checked_at", "provider"} {
+		if !strings.Contains(header, name) {
+			t.Errorf("Expected column %s in %s", name, header)
+		}
+	}
+	if strings.Contains(header, "renew_1y") {
+		t.Errorf("Expected unused price columns to be left out, got %s", header)
+	}
+
+	row := strings.Join(records[2], ",")
+	if !strings.Contains(row, "2030-01-02") || !strings.Contains(row, "active;locked") || !strings.Contains(row, "2026-10-01T12:00:00Z") {
+		t.Errorf("Unexpected row: %s", row)
+	}
+}
+
+func TestWrite_Markdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMarkdown, sampleResults()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("Expected header, rule and 3 rows, got %q", buf.String())
+	}
+	if !strings.HasPrefix(lines[1], "| --- |") {
+		t.Errorf("Expected a header rule, got %q", lines[1])
+	}
+	if !strings.Contains(lines[3], `Already \| registered`) {
+		t.Errorf("Expected pipes to be escaped, got %q", lines[3])
+	}
+}
+
+func TestWrite_JSONRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "saved.json")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(f, FormatJSON, sampleResults()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	f.Close()
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `"checked_at": "2026-10-01T12:00:00Z"`) || !strings.Contains(string(data), `"provider": "limoo"`) {
+		t.Errorf("Expected timestamp and provider in JSON, got %s", data)
+	}
+
+	items, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 3 || items[0].Prices.Register.TwoYears != 190000 || !items[0].CheckedAt.Equal(sampleResults()[0].CheckedAt) {
+		t.Errorf("Unexpected results after round trip: %+v", items)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, expected := range map[string]Format{"CSV": FormatCSV, "json": FormatJSON, "md": FormatMarkdown, "markdown": FormatMarkdown} {
+		if got, err := ParseFormat(input); err != nil || got != expected {
+			t.Errorf("%s: expected %q, got %q (%v)", input, expected, got, err)
+		}
+	}
+	if _, err := ParseFormat("xlsx"); err == nil {
+		t.Error("Expected error for an unknown format")
+	}
+}
+
+func TestStore_AppendLoad(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "data", "results.jsonl"))
+
+	items, err := store.Load()
+	if err != nil || len(items) != 0 {
+		t.Fatalf("Expected an empty store, got %v (%v)", items, err)
+	}
+
+	sample := sampleResults()
+	if err := store.Append(sample[:2]...); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := store.Append(sample[2]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	items, err = store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 3 || items[1].Domain != "example.net" || items[1].Expires == nil {
+		t.Errorf("Unexpected results: %+v", items)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items, _ := store.Load(); len(items) != 0 {
+		t.Errorf("Expected the store to be empty after Clear, got %d", len(items))
+	}
+}
+
+func TestStore_LoadSkipsMalformedLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	store := NewStore(path)
+	if err := store.Append(sampleResults()[0]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{\"domain\": \"trunc\n\nnot json\n{}\n")
+	f.Close()
+
+	if err := store.Append(sampleResults()[1]); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	items, err := store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[1].Domain != "example.net" {
+		t.Errorf("Unexpected results: %+v", items)
+	}
+	if got := store.Skipped(); got != 3 {
+		t.Errorf("Expected 3 skipped lines, got %d", got)
+	}
+}
+
+func TestStore_Compact(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "results.jsonl")
+	store := NewStore(path, WithLimit(2))
+
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 5 {
+		item := New(domain.DomainData{Domain: fmt.Sprintf("name%d.com", i)}, "mock", base.Add(time.Duration(i)*time.Minute))
+		if err := store.Append(item); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	items, err := store.Load()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].Domain != "name3.com" || items[1].Domain != "name4.com" {
+		t.Errorf("Expected the store to keep the 2 latest results, got %+v", items)
+	}
+}
+
+func TestFilter_Apply(t *testing.T) {
+	base := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	items := []Result{
+		New(domain.DomainData{Domain: "acme.com", Available: true}, "limoo", base),
+		New(domain.DomainData{Domain: "acme.net"}, "rdap", base.Add(time.Hour)),
+		New(domain.DomainData{Domain: "acme.com"}, "limoo", base.Add(2*time.Hour)),
+		New(domain.DomainData{Domain: "other.io", Available: true}, "limoo", base.Add(3*time.Hour)),
+	}
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []string
+	}{
+		{name: "latest per domain, newest first", filter: Filter{}, expected: []string{"other.io", "acme.com", "acme.net"}},
+		{name: "every lookup", filter: Filter{All: true}, expected: []string{"other.io", "acme.com", "acme.net", "acme.com"}},
+		{name: "available uses the latest answer", filter: Filter{Available: &yes}, expected: []string{"other.io"}},
+		{name: "taken", filter: Filter{Available: &no}, expected: []string{"acme.com", "acme.net"}},
+		{name: "domain text", filter: Filter{Domain: "ACME"}, expected: []string{"acme.com", "acme.net"}},
+		{name: "since", filter: Filter{Since: base.Add(90 * time.Minute)}, expected: []string{"other.io", "acme.com"}},
+		{name: "provider", filter: Filter{Provider: "RDAP"}, expected: []string{"acme.net"}},
+		{name: "limit", filter: Filter{Limit: 1}, expected: []string{"other.io"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, item := range tt.filter.Apply(items) {
+				got = append(got, item.Domain)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package results
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DefaultLimit = 10000
+
+type Store struct {
+	mu      sync.Mutex
+	path    string
+	limit   int
+	skipped int
+}
+
+type StoreOption func(*Store)
+
+func WithLimit(limit int) StoreOption {
+	return func(s *Store) {
+		s.limit = limit
+	}
+}
+
+func NewStore(path string, opts ...StoreOption) *Store {
+	s := &Store{path: path, limit: DefaultLimit}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Store) Path() string {
+	return s.path
+}
+
+func (s *Store) Append(items ...Result) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+
+	var buf strings.Builder
+	enc := json.NewEncoder(&buf)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(buf.String()); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if s.limit <= 0 {
+		return nil
+	}
+	lines, err := countLines(s.path)
+	if err != nil {
+		return err
+	}
+	if lines > 2*s.limit {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *Store) Load() ([]Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.read()
+}
+
+func (s *Store) Skipped() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.skipped
+}
+
+func (s *Store) read() ([]Result, error) {
+	s.skipped = 0
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []Result
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var item Result
+		if err := json.Unmarshal([]byte(text), &item); err != nil || item.Domain == "" {
+			s.skipped++
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, scanner.Err()
+}
+
+func (s *Store) compact() error {
+	items, err := s.read()
+	if err != nil {
+		return err
+	}
+	if len(items) > s.limit {
+		items = items[len(items)-s.limit:]
+	}
+
+	tmp := s.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	lines := 0
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := f.Read(buf)
+		lines += bytes.Count(buf[:n], []byte{'\n'})
+		if err == io.EOF {
+			return lines, nil
+		}
+		if err != nil {
+			return lines, err
+		}
+	}
+}
+
+func (s *Store) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+type Filter struct {
+	Domain    string
+	Available *bool
+	Since     time.Time
+	Provider  string
+	All       bool
+	Limit     int
+}
+
+func (f Filter) Apply(items []Result) []Result {
+	if !f.All {
+		items = Latest(items)
+	}
+
+	var matched []Result
+	for _, item := range items {
+		if f.Domain != "" && !strings.Contains(strings.ToLower(item.Domain), strings.ToLower(f.Domain)) {
+			continue
+		}
+		if f.Available != nil && item.Available != *f.Available {
+			continue
+		}
+		if !f.Since.IsZero() && item.CheckedAt.Before(f.Since) {
+			continue
+		}
+		if f.Provider != "" && !strings.EqualFold(item.Provider, f.Provider) {
+			continue
+		}
+		matched = append(matched, item)
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].CheckedAt.After(matched[j].CheckedAt)
+	})
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[:f.Limit]
+	}
+	return matched
+}
+
+func Latest(items []Result) []Result {
+	index := make(map[string]int, len(items))
+	var latest []Result
+	for _, item := range items {
+		key := strings.ToLower(item.Domain)
+		if i, ok := index[key]; ok {
+			if !item.CheckedAt.Before(latest[i].CheckedAt) {
+				latest[i] = item
+			}
+			continue
+		}
+		index[key] = len(latest)
+		latest = append(latest, item)
+	}
+	return latest
+}
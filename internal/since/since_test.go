@@ -0,0 +1,41 @@
+package since
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		input       string
+		expected    time.Time
+		expectError bool
+	}{
+		{input: "24h", expected: now.Add(-24 * time.Hour)},
+		{input: "90m", expected: now.Add(-90 * time.Minute)},
+		{input: "7d", expected: now.AddDate(0, 0, -7)},
+		{input: "2026-10-01", expected: time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)},
+		{input: "yesterday", expectError: true},
+		{input: "-1h", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input, now)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !got.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
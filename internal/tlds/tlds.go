@@ -0,0 +1,127 @@
+package tlds
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	SetAll     = "all"
+	DefaultSet = "popular"
+)
+
+var ErrUnknownSet = errors.New("unknown TLD set")
+
+//go:embed sets.json
+var defaultSets []byte
+
+type Sets map[string][]string
+
+func Default() Sets {
+	var sets Sets
+	if err := json.Unmarshal(defaultSets, &sets); err != nil {
+		panic("tlds: invalid embedded sets.json: " + err.Error())
+	}
+	return sets
+}
+
+func Load(path string) (Sets, error) {
+	sets := Default()
+	if path == "" {
+		return sets, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sets, nil
+		}
+		return sets, err
+	}
+
+	var custom Sets
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return sets, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for name, list := range custom {
+		sets[strings.ToLower(name)] = list
+	}
+	return sets, nil
+}
+
+func (s Sets) Names() []string {
+	names := make([]string, 0, len(s)+1)
+	for name := range s {
+		names = append(names, name)
+	}
+	if _, ok := s[SetAll]; !ok {
+		names = append(names, SetAll)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s Sets) Resolve(spec string) ([]string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "" {
+		spec = DefaultSet
+	}
+
+	if list, ok := s[spec]; ok {
+		return normalize(list), nil
+	}
+	if spec == SetAll {
+		var all []string
+		for _, list := range s {
+			all = append(all, list...)
+		}
+		tlds := normalize(all)
+		sort.Strings(tlds)
+		return tlds, nil
+	}
+
+	if !strings.Contains(spec, ",") && !s.known(strings.Trim(spec, ".")) {
+		return nil, fmt.Errorf("%w %q (sets: %s; add a comma for a one-TLD list, e.g. %q)", ErrUnknownSet, spec, strings.Join(s.Names(), ", "), strings.Trim(spec, ".")+",")
+	}
+
+	tlds := normalize(strings.Split(spec, ","))
+	if len(tlds) == 0 {
+		return nil, fmt.Errorf("no TLDs in %q", spec)
+	}
+	for _, tld := range tlds {
+		if strings.ContainsAny(tld, " /:") {
+			return nil, fmt.Errorf("invalid TLD %q (sets: %s)", tld, strings.Join(s.Names(), ", "))
+		}
+	}
+	return tlds, nil
+}
+
+func (s Sets) known(tld string) bool {
+	for _, list := range s {
+		for _, item := range list {
+			if strings.EqualFold(strings.Trim(item, "."), tld) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func normalize(list []string) []string {
+	seen := make(map[string]bool, len(list))
+	tlds := make([]string, 0, len(list))
+	for _, tld := range list {
+		tld = strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))
+		if tld == "" || seen[tld] {
+			continue
+		}
+		seen[tld] = true
+		tlds = append(tlds, tld)
+	}
+	return tlds
+}
@@ -0,0 +1,89 @@
+package tlds
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSets_Resolve(t *testing.T) {
+	sets := Sets{
+		"popular": {"com", "net", ".org"},
+		"iran":    {"ir", "co.ir", "com"},
+	}
+
+	tests := []struct {
+		spec        string
+		expected    []string
+		expectError bool
+	}{
+		{spec: "", expected: []string{"com", "net", "org"}},
+		{spec: "IRAN", expected: []string{"ir", "co.ir", "com"}},
+		{spec: "all", expected: []string{"co.ir", "com", "ir", "net", "org"}},
+		{spec: "io, .dev,io", expected: []string{"io", "dev"}},
+		{spec: "org", expected: []string{"org"}},
+		{spec: ".co.ir", expected: []string{"co.ir"}},
+		{spec: "xyz,", expected: []string{"xyz"}},
+		{spec: "xyz", expectError: true},
+		{spec: "popluar", expectError: true},
+		{spec: ",,", expectError: true},
+		{spec: "http://x", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := sets.Resolve(tt.spec)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				if !strings.Contains(tt.spec, ",") && !errors.Is(err, ErrUnknownSet) {
+					t.Errorf("Expected ErrUnknownSet, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tlds.json")
+
+	sets, err := Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(sets[DefaultSet]) == 0 || len(sets["iran"]) == 0 {
+		t.Errorf("Expected the bundled sets, got %v", sets)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"Popular": ["com"], "clients": ["shop", "store"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	sets, err = Load(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(sets["popular"], []string{"com"}) {
+		t.Errorf("Expected the file to override popular, got %v", sets["popular"])
+	}
+	if len(sets["clients"]) != 2 || len(sets["iran"]) == 0 {
+		t.Errorf("Expected custom and bundled sets, got %v", sets)
+	}
+
+	if err := os.WriteFile(path, []byte(`popular = com`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for an invalid file")
+	}
+}
@@ -0,0 +1,60 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+
+	"domainshell/internal/api"
+	"domainshell/pkg/domain"
+)
+
+func (c *Client) Capabilities() api.Capability {
+	return api.CapabilityCheck
+}
+
+func (c *Client) CheckAvailability(domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityContext(context.Background(), domainName)
+}
+
+func (c *Client) CheckAvailabilityContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(ctx, []string{domainName})
+}
+
+func (c *Client) CheckAvailabilityBatch(domainNames []string) (*domain.Response, error) {
+	return c.CheckAvailabilityBatchContext(context.Background(), domainNames)
+}
+
+func (c *Client) CheckAvailabilityBatchContext(ctx context.Context, domainNames []string) (*domain.Response, error) {
+	result := &domain.Response{Data: make([]domain.DomainData, 0, len(domainNames))}
+	for _, name := range domainNames {
+		record, err := c.Lookup(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		result.Data = append(result.Data, record.DomainData())
+	}
+	return result, nil
+}
+
+func (c *Client) SuggestDomains(domainName string) (*domain.Response, error) {
+	return nil, fmt.Errorf("whois: suggestions %w", api.ErrUnsupported)
+}
+
+func (c *Client) SuggestDomainsContext(ctx context.Context, domainName string) (*domain.Response, error) {
+	return c.SuggestDomains(domainName)
+}
+
+func (r *Record) DomainData() domain.DomainData {
+	item := domain.DomainData{
+		Domain:     r.Domain,
+		Available:  r.Available,
+		Registered: r.Created,
+		Expires:    r.Expires,
+		Registrar:  r.Registrar,
+		Status:     r.Status,
+	}
+	if !r.Available {
+		item.Reason = "Registered"
+	}
+	return item
+}
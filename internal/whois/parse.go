@@ -0,0 +1,142 @@
+package whois
+
+import (
+	"strings"
+	"time"
+)
+
+var (
+	registrarKeys   = []string{"registrar", "sponsoring registrar", "registrar name"}
+	createdKeys     = []string{"creation date", "created", "created date", "registered on", "registration time", "domain registration date"}
+	updatedKeys     = []string{"updated date", "last updated", "last-updated", "last-modified", "changed", "modified"}
+	expiresKeys     = []string{"registry expiry date", "registrar registration expiration date", "expiration date", "expiry date", "expire-date", "expire date", "expires", "paid-till", "domain expiration date"}
+	nameServerKeys  = []string{"name server", "nameserver", "nserver", "name servers"}
+	statusKeys      = []string{"domain status", "status"}
+	notFoundMarkers = []string{"no match", "not found", "no entries found", "no data found", "no object found", "status: free", "status: available", "is available for registration"}
+)
+
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"2006.01.02",
+	"2006/01/02",
+	"02-Jan-2006",
+	"02.01.2006",
+}
+
+type fieldMap map[string][]string
+
+func parseFields(raw string) fieldMap {
+	fields := make(fieldMap)
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ">>>") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if value != "" {
+			fields[key] = append(fields[key], value)
+		}
+	}
+	return fields
+}
+
+func (f fieldMap) first(keys ...string) string {
+	for _, key := range keys {
+		if values := f[key]; len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+func (f fieldMap) all(keys ...string) []string {
+	var values []string
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		for _, v := range f[key] {
+			if !seen[v] {
+				values = append(values, v)
+				seen[v] = true
+			}
+		}
+	}
+	return values
+}
+
+func (f fieldMap) date(keys ...string) *time.Time {
+	value := f.first(keys...)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+func parseRecord(name, server, raw string, fields fieldMap) *Record {
+	r := &Record{
+		Domain:    name,
+		Server:    server,
+		Raw:       raw,
+		Registrar: fields.first(registrarKeys...),
+		Created:   fields.date(createdKeys...),
+		Updated:   fields.date(updatedKeys...),
+		Expires:   fields.date(expiresKeys...),
+	}
+
+	for _, ns := range fields.all(nameServerKeys...) {
+		r.NameServers = append(r.NameServers, strings.ToLower(strings.Fields(ns)[0]))
+	}
+	for _, status := range fields.all(statusKeys...) {
+		r.Status = append(r.Status, strings.Fields(status)[0])
+	}
+
+	lower := strings.ToLower(raw)
+	for _, marker := range notFoundMarkers {
+		if strings.Contains(lower, marker) {
+			r.Available = true
+			break
+		}
+	}
+	if r.Registrar != "" || r.Created != nil || len(r.NameServers) > 0 {
+		r.Available = false
+	}
+
+	return r
+}
+
+func (r *Record) merge(other *Record) {
+	r.Server = other.Server
+	r.Raw = other.Raw
+	if other.Registrar != "" {
+		r.Registrar = other.Registrar
+	}
+	if other.Created != nil {
+		r.Created = other.Created
+	}
+	if other.Updated != nil {
+		r.Updated = other.Updated
+	}
+	if other.Expires != nil {
+		r.Expires = other.Expires
+	}
+	if len(other.NameServers) > 0 {
+		r.NameServers = other.NameServers
+	}
+	if len(other.Status) > 0 {
+		r.Status = other.Status
+	}
+}
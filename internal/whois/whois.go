@@ -0,0 +1,150 @@
+package whois
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	IANAServer     = "whois.iana.org"
+	defaultPort    = "43"
+	maxReferrals   = 3
+	maxResponse    = 1 << 20
+	defaultTimeout = 10 * time.Second
+)
+
+var ErrNoServer = errors.New("no WHOIS server known for this TLD")
+
+type Record struct {
+	Domain      string     `json:"domain"`
+	Server      string     `json:"server"`
+	Available   bool       `json:"available"`
+	Registrar   string     `json:"registrar,omitempty"`
+	Created     *time.Time `json:"created,omitempty"`
+	Updated     *time.Time `json:"updated,omitempty"`
+	Expires     *time.Time `json:"expires,omitempty"`
+	NameServers []string   `json:"name_servers,omitempty"`
+	Status      []string   `json:"status,omitempty"`
+	Raw         string     `json:"-"`
+}
+
+type Client struct {
+	server  string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+type Option func(*Client)
+
+func WithServer(server string) Option {
+	return func(c *Client) {
+		c.server = server
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		server:  IANAServer,
+		timeout: defaultTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *Client) Lookup(ctx context.Context, domainName string) (*Record, error) {
+	name := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domainName), "."))
+	if !strings.Contains(name, ".") {
+		return nil, fmt.Errorf("whois: %q is not a domain name", domainName)
+	}
+
+	raw, err := c.query(ctx, c.server, name)
+	if err != nil {
+		return nil, err
+	}
+
+	server := parseFields(raw).first("refer", "whois")
+	if server == "" {
+		return nil, fmt.Errorf("whois: %s: %w", name, ErrNoServer)
+	}
+
+	var record *Record
+	visited := make(map[string]bool)
+
+	for hop := 0; hop < maxReferrals && server != "" && !visited[server]; hop++ {
+		visited[server] = true
+
+		raw, err := c.query(ctx, server, name)
+		if err != nil {
+			if record != nil {
+				return record, nil
+			}
+			return nil, err
+		}
+
+		fields := parseFields(raw)
+		parsed := parseRecord(name, server, raw, fields)
+		if record == nil {
+			record = parsed
+		} else {
+			record.merge(parsed)
+		}
+
+		server = fields.first("registrar whois server")
+	}
+
+	return record, nil
+}
+
+func (c *Client) query(ctx context.Context, server, name string) (string, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	addr := server
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		addr = net.JoinHostPort(server, defaultPort)
+	}
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("whois: %w", err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	if _, err := io.WriteString(conn, name+"\r\n"); err != nil {
+		return "", fmt.Errorf("whois: %w", err)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(conn, maxResponse))
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("whois: %w", ctx.Err())
+		}
+		return "", fmt.Errorf("whois: %w", err)
+	}
+
+	return string(data), nil
+}
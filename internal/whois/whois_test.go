@@ -0,0 +1,186 @@
+package whois
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startServer(t *testing.T, respond func(query string) string) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				query, _ := bufio.NewReader(conn).ReadString('\n')
+				fmt.Fprint(conn, respond(strings.TrimSpace(query)))
+			}()
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func newTestClient(t *testing.T) *Client {
+	t.Helper()
+
+	registrar := startServer(t, func(query string) string {
+		return "Domain Name: " + strings.ToUpper(query) + "\r\n" +
+			"Registrar: Example Registrar, Inc.\r\n" +
+			"Registrar Registration Expiration Date: 2026-08-13T04:00:00Z\r\n" +
+			"Name Server: NS1.EXAMPLE.NET\r\n"
+	})
+
+	registry := startServer(t, func(query string) string {
+		if query == "free-name.com" {
+			return "No match for \"FREE-NAME.COM\".\r\n>>> Last update of whois database: 2025-11-29T10:00:00Z <<<\r\n"
+		}
+		return "   Domain Name: EXAMPLE.COM\r\n" +
+			"   Registrar WHOIS Server: " + registrar + "\r\n" +
+			"   Creation Date: 1995-08-14T04:00:00Z\r\n" +
+			"   Registry Expiry Date: 2026-08-13T04:00:00Z\r\n" +
+			"   Domain Status: clientDeleteProhibited https://icann.org/epp#clientDeleteProhibited\r\n" +
+			"   Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited\r\n" +
+			"   Name Server: A.IANA-SERVERS.NET\r\n" +
+			"   Name Server: B.IANA-SERVERS.NET\r\n"
+	})
+
+	iana := startServer(t, func(query string) string {
+		if strings.HasSuffix(query, ".com") {
+			return "% IANA WHOIS server\r\n\r\nrefer:        " + registry + "\r\n\r\ndomain:       COM\r\n"
+		}
+		return "% IANA WHOIS server\r\n% This query returned 0 objects.\r\n"
+	})
+
+	return NewClient(WithServer(iana), WithTimeout(2*time.Second))
+}
+
+func TestClient_LookupFollowsReferrals(t *testing.T) {
+	client := newTestClient(t)
+
+	record, err := client.Lookup(context.Background(), "Example.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if record.Available {
+		t.Error("Expected example.com to be registered")
+	}
+	if record.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Expected registrar from the registrar server, got %q", record.Registrar)
+	}
+	if record.Created == nil || record.Created.Year() != 1995 {
+		t.Errorf("Expected creation date from the registry, got %v", record.Created)
+	}
+	if record.Expires == nil || record.Expires.Year() != 2026 {
+		t.Errorf("Unexpected expiry date: %v", record.Expires)
+	}
+	if len(record.NameServers) != 1 || record.NameServers[0] != "ns1.example.net" {
+		t.Errorf("Expected name servers from the registrar server, got %v", record.NameServers)
+	}
+	if len(record.Status) != 2 || record.Status[0] != "clientDeleteProhibited" {
+		t.Errorf("Unexpected status: %v", record.Status)
+	}
+}
+
+func TestClient_LookupAvailable(t *testing.T) {
+	client := newTestClient(t)
+
+	resp, err := client.CheckAvailability("free-name.com")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !resp.Data[0].Available {
+		t.Error("Expected free-name.com to be available")
+	}
+}
+
+func TestClient_LookupErrors(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := client.Lookup(context.Background(), "example.zz"); !errors.Is(err, ErrNoServer) {
+		t.Errorf("Expected ErrNoServer, got %v", err)
+	}
+	if _, err := client.Lookup(context.Background(), "localhost"); err == nil {
+		t.Error("Expected error for a name without a TLD")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Lookup(ctx, "example.com"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context canceled, got %v", err)
+	}
+}
+
+func TestParseRecord(t *testing.T) {
+	tests := []struct {
+		name            string
+		raw             string
+		available       bool
+		registrar       string
+		expires         string
+		nameServerCount int
+	}{
+		{
+			name: "nic.ir format",
+			raw: "% This is the IRNIC Whois server.\n" +
+				"domain:\t\texample.ir\n" +
+				"ascii:\t\texample.ir\n" +
+				"last-updated:\t2025-01-10\n" +
+				"expire-date:\t2026-03-21\n" +
+				"holder-c:\tex123-irnic\n" +
+				"nserver:\tns1.example.ir\n" +
+				"nserver:\tns2.example.ir\n",
+			expires:         "2026-03-21",
+			nameServerCount: 2,
+		},
+		{
+			name:      "not found",
+			raw:       "%ERROR:101: no entries found\n",
+			available: true,
+		},
+		{
+			name: "sponsoring registrar",
+			raw: "Domain Name: EXAMPLE.ORG\n" +
+				"Sponsoring Registrar: Example Registrar\n" +
+				"Registry Expiry Date: 2027-01-02T00:00:00Z\n",
+			registrar: "Example Registrar",
+			expires:   "2027-01-02",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := parseRecord("example", "whois.test", tt.raw, parseFields(tt.raw))
+
+			if record.Available != tt.available {
+				t.Errorf("Expected available=%v, got %v", tt.available, record.Available)
+			}
+			if record.Registrar != tt.registrar {
+				t.Errorf("Expected registrar %q, got %q", tt.registrar, record.Registrar)
+			}
+			if tt.expires != "" && (record.Expires == nil || record.Expires.Format(time.DateOnly) != tt.expires) {
+				t.Errorf("Expected expiry %s, got %v", tt.expires, record.Expires)
+			}
+			if len(record.NameServers) != tt.nameServerCount {
+				t.Errorf("Expected %d name servers, got %v", tt.nameServerCount, record.NameServers)
+			}
+		})
+	}
+}
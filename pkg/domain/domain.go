@@ -1,16 +1,86 @@
 package domain
 
+import "time"
+
+const SourceDNS = "dns"
+
+const MaxYears = 10
+
+type PeriodPrices struct {
+	OneYear    int `json:"1y"`
+	TwoYears   int `json:"2y,omitempty"`
+	ThreeYears int `json:"3y,omitempty"`
+	FourYears  int `json:"4y,omitempty"`
+	FiveYears  int `json:"5y,omitempty"`
+	SixYears   int `json:"6y,omitempty"`
+	SevenYears int `json:"7y,omitempty"`
+	EightYears int `json:"8y,omitempty"`
+	NineYears  int `json:"9y,omitempty"`
+	TenYears   int `json:"10y,omitempty"`
+}
+
+func (p PeriodPrices) For(years int) int {
+	periods := []int{
+		p.OneYear, p.TwoYears, p.ThreeYears, p.FourYears, p.FiveYears,
+		p.SixYears, p.SevenYears, p.EightYears, p.NineYears, p.TenYears,
+	}
+	if years < 1 || years > len(periods) {
+		return 0
+	}
+	return periods[years-1]
+}
+
+type Prices struct {
+	Register PeriodPrices `json:"register"`
+	Renew    PeriodPrices `json:"renew,omitzero"`
+	Transfer PeriodPrices `json:"transfer,omitzero"`
+	Restore  PeriodPrices `json:"restore,omitzero"`
+}
+
+func (p Prices) RenewalPrice() int {
+	if p.Renew.OneYear > 0 {
+		return p.Renew.OneYear
+	}
+	return p.Register.OneYear
+}
+
+func (p Prices) TotalCost(years int) int {
+	if years < 1 || p.Register.OneYear == 0 {
+		return 0
+	}
+	if upfront := p.Register.For(years); upfront > 0 {
+		return upfront
+	}
+	return p.Register.OneYear + p.RenewalCost(years-1)
+}
+
+func (p Prices) RenewalCost(years int) int {
+	costs := make([]int, max(years, 0)+1)
+	for y := 1; y <= years; y++ {
+		costs[y] = costs[y-1] + p.RenewalPrice()
+		for k := 2; k <= min(y, MaxYears); k++ {
+			if tier := p.Renew.For(k); tier > 0 && costs[y-k]+tier < costs[y] {
+				costs[y] = costs[y-k] + tier
+			}
+		}
+	}
+	return costs[max(years, 0)]
+}
+
 type DomainData struct {
-	Available bool   `json:"available"`
-	Domain    string `json:"domain"`
-	OnSale    bool   `json:"on_sale"`
-	Premium   bool   `json:"premium"`
-	Prices    struct {
-		Register struct {
-			OneYear int `json:"1y"`
-		} `json:"register"`
-	} `json:"prices"`
-	Reason string `json:"reason"`
+	Available  bool       `json:"available"`
+	Domain     string     `json:"domain"`
+	OnSale     bool       `json:"on_sale"`
+	Premium    bool       `json:"premium"`
+	Prices     Prices     `json:"prices"`
+	Reason     string     `json:"reason"`
+	Cached     bool       `json:"cached,omitempty"`
+	CachedAt   time.Time  `json:"cached_at,omitzero"`
+	Registered *time.Time `json:"registered,omitempty"`
+	Expires    *time.Time `json:"expires,omitempty"`
+	Registrar  string     `json:"registrar,omitempty"`
+	Status     []string   `json:"status,omitempty"`
+	Source     string     `json:"source,omitempty"`
 }
 
 type Response struct {
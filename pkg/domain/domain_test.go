@@ -30,14 +30,8 @@ func TestDomainData_JSON(t *testing.T) {
 				Domain:    "example.com",
 				OnSale:    true,
 				Premium:   false,
-				Prices: struct {
-					Register struct {
-						OneYear int `json:"1y"`
-					} `json:"register"`
-				}{
-					Register: struct {
-						OneYear int `json:"1y"`
-					}{
+				Prices: Prices{
+					Register: PeriodPrices{
 						OneYear: 100000,
 					},
 				},
@@ -157,3 +151,112 @@ func TestResponse_EmptyData(t *testing.T) {
 		t.Errorf("Expected empty data, got %d items", len(response.Data))
 	}
 }
+
+func TestPrices_JSON(t *testing.T) {
+	jsonStr := `{
+		"register": {"1y": 100000, "2y": 190000, "10y": 900000},
+		"renew": {"1y": 150000},
+		"transfer": {"1y": 120000},
+		"restore": {"1y": 500000}
+	}`
+
+	var prices Prices
+	if err := json.Unmarshal([]byte(jsonStr), &prices); err != nil {
+		t.Fatalf("Failed to unmarshal JSON: %v", err)
+	}
+
+	if prices.Register.For(2) != 190000 || prices.Register.For(10) != 900000 {
+		t.Errorf("Unexpected multi-year register prices: %+v", prices.Register)
+	}
+	if prices.Register.For(3) != 0 || prices.Register.For(11) != 0 {
+		t.Error("Expected zero for missing periods")
+	}
+	if prices.Renew.OneYear != 150000 || prices.Transfer.OneYear != 120000 || prices.Restore.OneYear != 500000 {
+		t.Errorf("Unexpected prices: %+v", prices)
+	}
+
+	out, err := json.Marshal(Prices{Register: PeriodPrices{OneYear: 100000}})
+	if err != nil {
+		t.Fatalf("Failed to marshal prices: %v", err)
+	}
+	if string(out) != `{"register":{"1y":100000}}` {
+		t.Errorf("Expected register-only prices to keep the original shape, got %s", out)
+	}
+}
+
+func TestPrices_TotalCost(t *testing.T) {
+	tests := []struct {
+		name     string
+		prices   Prices
+		years    int
+		expected int
+	}{
+		{
+			name:     "renewal defaults to register price",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100}},
+			years:    3,
+			expected: 300,
+		},
+		{
+			name:     "renewal trap",
+			prices:   Prices{Register: PeriodPrices{OneYear: 10}, Renew: PeriodPrices{OneYear: 100}},
+			years:    5,
+			expected: 410,
+		},
+		{
+			name:     "single year",
+			prices:   Prices{Register: PeriodPrices{OneYear: 10}, Renew: PeriodPrices{OneYear: 100}},
+			years:    1,
+			expected: 10,
+		},
+		{
+			name:     "multi-year register price",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100, TwoYears: 150}, Renew: PeriodPrices{OneYear: 120}},
+			years:    2,
+			expected: 150,
+		},
+		{
+			name:     "multi-year renewal tiers",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100}, Renew: PeriodPrices{OneYear: 120, TwoYears: 200}},
+			years:    5,
+			expected: 100 + 200 + 200,
+		},
+		{
+			name:     "odd years fall back to the one-year renewal",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100}, Renew: PeriodPrices{OneYear: 120, TwoYears: 200}},
+			years:    4,
+			expected: 100 + 200 + 120,
+		},
+		{
+			name:     "expensive tier is not used",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100}, Renew: PeriodPrices{OneYear: 120, ThreeYears: 500}},
+			years:    4,
+			expected: 100 + 3*120,
+		},
+		{
+			name:     "beyond ten years",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100}, Renew: PeriodPrices{OneYear: 120, TenYears: 1000}},
+			years:    12,
+			expected: 100 + 1000 + 120,
+		},
+		{
+			name:     "no pricing",
+			years:    5,
+			expected: 0,
+		},
+		{
+			name:     "invalid period",
+			prices:   Prices{Register: PeriodPrices{OneYear: 100}},
+			years:    0,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prices.TotalCost(tt.years); got != tt.expected {
+				t.Errorf("Expected %d, got %d", tt.expected, got)
+			}
+		})
+	}
+}